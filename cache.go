@@ -0,0 +1,49 @@
+package weatherline
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Cache : API レスポンスなどのキャッシュ interface
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte) error
+}
+
+type fileCache struct {
+	dir string
+}
+
+// NewFileCache : Create Cache instance which stores values as files in dir
+func NewFileCache(dir string) Cache {
+	return &fileCache{
+		dir: dir,
+	}
+}
+
+func (c *fileCache) path(key string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x", sha1.Sum([]byte(key))))
+}
+
+// Get : Cache.Get の実装
+func (c *fileCache) Get(key string) ([]byte, bool) {
+	b, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	return b, true
+}
+
+// Set : Cache.Set の実装
+func (c *fileCache) Set(key string, value []byte) error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path(key), value, 0600)
+}
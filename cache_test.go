@@ -0,0 +1,56 @@
+package weatherline
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewFileCache(t *testing.T) {
+	dir := "/tmp/weatherline"
+
+	cache := NewFileCache(dir)
+	if cache == nil {
+		t.Fatal("function returns nil")
+	}
+
+	c, ok := cache.(*fileCache)
+	if !ok {
+		t.Fatal("Expected fileCache instance, but not.")
+	}
+
+	if c.dir != dir {
+		t.Fatalf("Expected dir is %s, but it's %s.", dir, c.dir)
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	tempDir, err := ioutil.TempDir(os.TempDir(), "wl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	c := &fileCache{dir: filepath.Join(tempDir, "cache")}
+
+	if _, ok := c.Get("key"); ok {
+		t.Fatal("Expected no value is cached, but it is")
+	}
+
+	if err := c.Set("key", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	v, ok := c.Get("key")
+	if !ok {
+		t.Fatal("Expected value is cached, but it is not")
+	}
+	if string(v) != "value" {
+		t.Fatalf("Expected to get [%s], but got [%s]", "value", string(v))
+	}
+
+	if _, ok := c.Get("other"); ok {
+		t.Fatal("Expected no value is cached, but it is")
+	}
+}
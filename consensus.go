@@ -0,0 +1,308 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConsensusError : すべてのプロバイダで予報を取得できなかったときのエラー
+type ConsensusError map[string]error
+
+func (e ConsensusError) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := []string{}
+	for _, name := range names {
+		errs = append(errs, fmt.Sprintf("%s: %v", name, e[name]))
+	}
+
+	return fmt.Sprintf("all providers failed [%s]", strings.Join(errs, ", "))
+}
+
+type consensus struct {
+	names     []string
+	forecasts map[string]Forecast
+}
+
+// NewConsensus : 複数の Forecast から並行して予報を取得し、まとめる Forecast を作成する
+//
+// 気温は中央値、降水確率は最大値、天気は多数決で決める。
+// 一部のプロバイダで取得に失敗しても残りの予報を返し、予報に使ったプロバイダを Report.Sources に、
+// 失敗したプロバイダとそのエラーを Report.Failures に設定する。
+func NewConsensus(forecasts map[string]Forecast) Forecast {
+	names := make([]string, 0, len(forecasts))
+	for name := range forecasts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &consensus{
+		names:     names,
+		forecasts: forecasts,
+	}
+}
+
+// Get : Forecast.Get の実装
+func (f *consensus) Get(lang Lang, units Units, opts ...GetOption) (*Report, error) {
+	reports := make([]*Report, len(f.names))
+	errs := make([]error, len(f.names))
+
+	var wg sync.WaitGroup
+	for i, name := range f.names {
+		fore := f.forecasts[name]
+		if fore == nil {
+			// 設定が不正で作成できなかったプロバイダは取得に失敗したものとして扱う
+			errs[i] = fmt.Errorf("forecast is not available")
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, fore Forecast) {
+			defer wg.Done()
+			reports[i], errs[i] = fore.Get(lang, units, opts...)
+		}(i, fore)
+	}
+	wg.Wait()
+
+	sources := []string{}
+	succeeded := []*Report{}
+	failed := ConsensusError{}
+	for i, name := range f.names {
+		if errs[i] != nil {
+			failed[name] = errs[i]
+			continue
+		}
+		if reports[i] == nil {
+			continue
+		}
+
+		sources = append(sources, name)
+		succeeded = append(succeeded, reports[i])
+	}
+
+	if len(succeeded) == 0 {
+		return nil, failed
+	}
+
+	report := mergeReports(succeeded)
+	report.Sources = sources
+	if len(failed) > 0 {
+		report.Failures = failed
+	}
+
+	return report, nil
+}
+
+// mergeReports : 複数の予報を1つにまとめる
+//
+// タイムゾーンと単位系は最初の予報のものを使う
+func mergeReports(reports []*Report) *Report {
+	loc := reports[0].Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	units := reports[0].Units
+	converted := make([]*Report, len(reports))
+	for i, r := range reports {
+		converted[i] = convertReport(r, units)
+	}
+	reports = converted
+
+	// 時間別予報は同じ時刻のものをまとめる
+	hourlyTimes := []int64{}
+	hourly := map[int64][]HourlyPoint{}
+	for _, r := range reports {
+		for _, p := range r.Hourly {
+			key := p.Time.Unix()
+			if _, ok := hourly[key]; !ok {
+				hourlyTimes = append(hourlyTimes, key)
+			}
+			hourly[key] = append(hourly[key], p)
+		}
+	}
+	sort.Slice(hourlyTimes, func(i, j int) bool { return hourlyTimes[i] < hourlyTimes[j] })
+
+	// 日別予報は同じ日付のものをまとめる
+	days := []string{}
+	daily := map[string][]DailyPoint{}
+	for _, r := range reports {
+		for _, p := range r.Daily {
+			key := p.Time.In(loc).Format("2006-01-02")
+			if _, ok := daily[key]; !ok {
+				days = append(days, key)
+			}
+			daily[key] = append(daily[key], p)
+		}
+	}
+	sort.Strings(days)
+
+	report := &Report{
+		Location: loc,
+		Units:    units,
+	}
+	for _, key := range hourlyTimes {
+		report.Hourly = append(report.Hourly, mergeHourly(loc, hourly[key]))
+	}
+	for _, key := range days {
+		report.Daily = append(report.Daily, mergeDaily(loc, daily[key]))
+	}
+
+	// 現在の天気と1分ごとの予報は最初に得られたものを使う
+	for _, r := range reports {
+		if r.Currently != nil {
+			p := *r.Currently
+			p.Time = p.Time.In(loc)
+			report.Currently = &p
+			break
+		}
+	}
+	for _, r := range reports {
+		if len(r.Minutely) > 0 {
+			report.Minutely = r.Minutely
+			report.MinutelySummary = r.MinutelySummary
+			break
+		}
+	}
+
+	// 気象警報も最初に得られたものを使う
+	for _, r := range reports {
+		if len(r.Alerts) > 0 {
+			report.Alerts = r.Alerts
+			break
+		}
+	}
+
+	return report
+}
+
+func mergeHourly(loc *time.Location, points []HourlyPoint) HourlyPoint {
+	p := HourlyPoint{
+		Time: points[0].Time.In(loc),
+	}
+
+	weathers := []Weather{}
+	for _, point := range points {
+		weathers = append(weathers, point.Weather)
+	}
+	p.Weather = dominantWeather(weathers)
+	for _, point := range points {
+		if point.Weather == p.Weather {
+			p.Summary = point.Summary
+			break
+		}
+	}
+
+	p.Temperature = median(len(points), func(i int) float64 { return points[i].Temperature })
+	p.ApparentTemperature = median(len(points), func(i int) float64 { return points[i].ApparentTemperature })
+	p.PrecipProbability = maximum(len(points), func(i int) float64 { return points[i].PrecipProbability })
+	p.PrecipAccumulation = median(len(points), func(i int) float64 { return points[i].PrecipAccumulation })
+
+	// その他の気象要素は最初に得られたものを使う
+	for _, point := range points {
+		if point.Conditions != nil {
+			p.Conditions = point.Conditions
+			break
+		}
+	}
+
+	return p
+}
+
+func mergeDaily(loc *time.Location, points []DailyPoint) DailyPoint {
+	t := points[0].Time.In(loc)
+	p := DailyPoint{
+		Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc),
+	}
+
+	weathers := []Weather{}
+	for _, point := range points {
+		weathers = append(weathers, point.Weather)
+	}
+	p.Weather = dominantWeather(weathers)
+	for _, point := range points {
+		if point.Weather == p.Weather {
+			p.Summary = point.Summary
+			break
+		}
+	}
+
+	p.TemperatureHigh = median(len(points), func(i int) float64 { return points[i].TemperatureHigh })
+	p.TemperatureHighTime = medianTime(loc, len(points), func(i int) time.Time { return points[i].TemperatureHighTime })
+	p.TemperatureLow = median(len(points), func(i int) float64 { return points[i].TemperatureLow })
+	p.TemperatureLowTime = medianTime(loc, len(points), func(i int) time.Time { return points[i].TemperatureLowTime })
+	p.ApparentTemperatureHigh = median(len(points), func(i int) float64 { return points[i].ApparentTemperatureHigh })
+	p.ApparentTemperatureHighTime = medianTime(loc, len(points), func(i int) time.Time { return points[i].ApparentTemperatureHighTime })
+	p.ApparentTemperatureLow = median(len(points), func(i int) float64 { return points[i].ApparentTemperatureLow })
+	p.ApparentTemperatureLowTime = medianTime(loc, len(points), func(i int) time.Time { return points[i].ApparentTemperatureLowTime })
+	p.PrecipProbability = maximum(len(points), func(i int) float64 { return points[i].PrecipProbability })
+	p.PrecipAccumulation = median(len(points), func(i int) float64 { return points[i].PrecipAccumulation })
+
+	for _, point := range points {
+		if point.Conditions != nil {
+			p.Conditions = point.Conditions
+			break
+		}
+	}
+
+	return p
+}
+
+// median : NaN を除いた値の中央値を求める (値がなければ NaN)
+func median(n int, value func(int) float64) float64 {
+	values := []float64{}
+	for i := 0; i < n; i++ {
+		if v := value(i); !math.IsNaN(v) {
+			values = append(values, v)
+		}
+	}
+
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	sort.Float64s(values)
+	m := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[m]
+	}
+
+	return (values[m-1] + values[m]) / 2
+}
+
+// medianTime : ゼロ値を除いた時刻の中央値を求める (時刻がなければゼロ値)
+func medianTime(loc *time.Location, n int, value func(int) time.Time) time.Time {
+	unix := median(n, func(i int) float64 {
+		t := value(i)
+		if t.IsZero() {
+			return math.NaN()
+		}
+		return float64(t.Unix())
+	})
+
+	if math.IsNaN(unix) {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(unix), 0).In(loc)
+}
+
+// maximum : NaN を除いた値の最大値を求める (値がなければ NaN)
+func maximum(n int, value func(int) float64) float64 {
+	max := math.NaN()
+	for i := 0; i < n; i++ {
+		if v := value(i); !math.IsNaN(v) && (math.IsNaN(max) || v > max) {
+			max = v
+		}
+	}
+
+	return max
+}
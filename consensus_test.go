@@ -0,0 +1,332 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConsensusError_Error(t *testing.T) {
+	err := ConsensusError{
+		"jma":        fmt.Errorf("404: Not Found"),
+		"open-meteo": fmt.Errorf("400: Invalid latitude"),
+	}
+
+	expected := "all providers failed [jma: 404: Not Found, open-meteo: 400: Invalid latitude]"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		values   []float64
+		expected float64
+	}{
+		// TEST0 {{{
+		{
+			values:   []float64{3, 1, 2},
+			expected: 2,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			values:   []float64{4, 1, 2, 3},
+			expected: 2.5,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			values:   []float64{math.NaN(), 5, math.NaN()},
+			expected: 5,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			values:   []float64{math.NaN()},
+			expected: math.NaN(),
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			values:   []float64{},
+			expected: math.NaN(),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := median(len(tt.values), func(i int) float64 { return tt.values[i] })
+			if !sameValue(actual, tt.expected) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+type stubForecast struct {
+	report *Report
+	err    error
+}
+
+func (f *stubForecast) Get(lang Lang, units Units, _ ...GetOption) (*Report, error) {
+	return f.report, f.err
+}
+
+func TestNewConsensus(t *testing.T) {
+	forecasts := map[string]Forecast{
+		"open-meteo": &stubForecast{},
+		"darksky":    &stubForecast{},
+		"jma":        &stubForecast{},
+	}
+
+	fore := NewConsensus(forecasts)
+	if fore == nil {
+		t.Fatal("function returns nil")
+	}
+
+	f, ok := fore.(*consensus)
+	if !ok {
+		t.Fatal("Expected consensus instance, but not.")
+	}
+
+	expectedNames := []string{"darksky", "jma", "open-meteo"}
+	if !reflect.DeepEqual(f.names, expectedNames) {
+		t.Fatalf("Expected names are %v, but they're %v.", expectedNames, f.names)
+	}
+}
+
+func TestConsensus_Get(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+	day0 := time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo)
+
+	report := func(weather Weather, summary string, temperature, probability float64, highTime time.Time) *Report {
+		return &Report{
+			Location: tokyo,
+			Hourly: []HourlyPoint{
+				{
+					Time:                day0.Add(9 * time.Hour),
+					Weather:             weather,
+					Summary:             summary,
+					Temperature:         temperature,
+					ApparentTemperature: temperature - 2,
+					PrecipProbability:   probability,
+					PrecipAccumulation:  math.NaN(),
+				},
+			},
+			Daily: []DailyPoint{
+				{
+					Time:                        day0,
+					Weather:                     weather,
+					Summary:                     summary,
+					TemperatureHigh:             temperature + 5,
+					TemperatureHighTime:         highTime,
+					TemperatureLow:              temperature - 5,
+					TemperatureLowTime:          day0.Add(5 * time.Hour),
+					ApparentTemperatureHigh:     temperature + 3,
+					ApparentTemperatureHighTime: highTime,
+					ApparentTemperatureLow:      temperature - 7,
+					ApparentTemperatureLowTime:  day0.Add(5 * time.Hour),
+					PrecipProbability:           probability,
+					PrecipAccumulation:          math.NaN(),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		forecasts map[string]Forecast
+
+		expected      *Report
+		expectedError error
+	}{
+		// TEST0 {{{
+		{
+			forecasts: map[string]Forecast{
+				"darksky":    &stubForecast{report: report(WeatherRain, "Rain", 4, 0.8, day0.Add(13*time.Hour))},
+				"jma":        &stubForecast{report: report(WeatherCloudy, "くもり", 6, math.NaN(), time.Time{})},
+				"open-meteo": &stubForecast{report: report(WeatherRain, "Slight rain", 5, 0.6, day0.Add(15*time.Hour))},
+			},
+
+			expected: &Report{
+				Location: tokyo,
+				Hourly: []HourlyPoint{
+					{
+						Time:                day0.Add(9 * time.Hour),
+						Weather:             WeatherRain,
+						Summary:             "Rain",
+						Temperature:         5,
+						ApparentTemperature: 3,
+						PrecipProbability:   0.8,
+						PrecipAccumulation:  math.NaN(),
+					},
+				},
+				Daily: []DailyPoint{
+					{
+						Time:                        day0,
+						Weather:                     WeatherRain,
+						Summary:                     "Rain",
+						TemperatureHigh:             10,
+						TemperatureHighTime:         day0.Add(14 * time.Hour),
+						TemperatureLow:              0,
+						TemperatureLowTime:          day0.Add(5 * time.Hour),
+						ApparentTemperatureHigh:     8,
+						ApparentTemperatureHighTime: day0.Add(14 * time.Hour),
+						ApparentTemperatureLow:      -2,
+						ApparentTemperatureLowTime:  day0.Add(5 * time.Hour),
+						PrecipProbability:           0.8,
+						PrecipAccumulation:          math.NaN(),
+					},
+				},
+				Sources: []string{"darksky", "jma", "open-meteo"},
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			forecasts: map[string]Forecast{
+				"darksky":    &stubForecast{err: fmt.Errorf("403: Forbidden")},
+				"jma":        &stubForecast{report: report(WeatherCloudy, "くもり", 6, math.NaN(), time.Time{})},
+				"open-meteo": &stubForecast{report: report(WeatherRain, "Slight rain", 5, 0.6, day0.Add(15*time.Hour))},
+			},
+
+			expected: &Report{
+				Location: tokyo,
+				Hourly: []HourlyPoint{
+					{
+						Time:                day0.Add(9 * time.Hour),
+						Weather:             WeatherRain,
+						Summary:             "Slight rain",
+						Temperature:         5.5,
+						ApparentTemperature: 3.5,
+						PrecipProbability:   0.6,
+						PrecipAccumulation:  math.NaN(),
+					},
+				},
+				Daily: []DailyPoint{
+					{
+						Time:                        day0,
+						Weather:                     WeatherRain,
+						Summary:                     "Slight rain",
+						TemperatureHigh:             10.5,
+						TemperatureHighTime:         day0.Add(15 * time.Hour),
+						TemperatureLow:              0.5,
+						TemperatureLowTime:          day0.Add(5 * time.Hour),
+						ApparentTemperatureHigh:     8.5,
+						ApparentTemperatureHighTime: day0.Add(15 * time.Hour),
+						ApparentTemperatureLow:      -1.5,
+						ApparentTemperatureLowTime:  day0.Add(5 * time.Hour),
+						PrecipProbability:           0.6,
+						PrecipAccumulation:          math.NaN(),
+					},
+				},
+				Sources:  []string{"jma", "open-meteo"},
+				Failures: map[string]error{"darksky": fmt.Errorf("403: Forbidden")},
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			forecasts: map[string]Forecast{
+				"darksky": &stubForecast{err: fmt.Errorf("403: Forbidden")},
+				"jma":     &stubForecast{err: fmt.Errorf("404: Not Found")},
+			},
+
+			expectedError: ConsensusError{
+				"darksky": fmt.Errorf("403: Forbidden"),
+				"jma":     fmt.Errorf("404: Not Found"),
+			},
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			forecasts: map[string]Forecast{
+				"darksky": nil,
+				"jma":     &stubForecast{err: fmt.Errorf("404: Not Found")},
+			},
+
+			expectedError: ConsensusError{
+				"darksky": fmt.Errorf("forecast is not available"),
+				"jma":     fmt.Errorf("404: Not Found"),
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			res, err := NewConsensus(tt.forecasts).Get(LangEn, UnitsSI)
+			if err != nil {
+				if tt.expectedError == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err.Error() != tt.expectedError.Error() {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expectedError, err)
+				}
+				return
+			}
+
+			if tt.expectedError != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+				return
+			}
+
+			if res.Location.String() != tt.expected.Location.String() {
+				t.Errorf("Expected location is %s, but it's %s", tt.expected.Location, res.Location)
+			}
+			if !sameValue(res.Hourly, tt.expected.Hourly) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected.Hourly, res.Hourly)
+			}
+			if !sameValue(res.Daily, tt.expected.Daily) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected.Daily, res.Daily)
+			}
+			if !reflect.DeepEqual(res.Sources, tt.expected.Sources) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected.Sources, res.Sources)
+			}
+			if fmt.Sprint(res.Failures) != fmt.Sprint(tt.expected.Failures) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected.Failures, res.Failures)
+			}
+		})
+	}
+}
+
+func TestConsensus_Get_currently(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+	now := time.Date(2018, 1, 31, 9, 2, 0, 0, tokyo)
+
+	forecasts := map[string]Forecast{
+		"darksky": &stubForecast{report: &Report{
+			Location:        tokyo,
+			Units:           UnitsSI,
+			Currently:       &HourlyPoint{Time: now, Weather: WeatherClearDay, Temperature: 2.5},
+			Minutely:        []MinutelyPoint{{Time: now, PrecipIntensity: 0.5, PrecipProbability: 0.3, PrecipType: "rain"}},
+			MinutelySummary: "Light rain",
+		}},
+		"jma": &stubForecast{report: &Report{Location: tokyo, Units: UnitsSI}},
+	}
+
+	res, err := NewConsensus(forecasts).Get(LangEn, UnitsSI, WithBlocks(BlockCurrently, BlockMinutely))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Currently == nil || res.Currently.Temperature != 2.5 || !res.Currently.Time.Equal(now) {
+		t.Errorf("Expected to get the current weather, but got [%+v]", res.Currently)
+	}
+	if len(res.Minutely) != 1 || res.Minutely[0].PrecipIntensity != 0.5 {
+		t.Errorf("Expected to get the minutely forecast, but got [%+v]", res.Minutely)
+	}
+	if res.MinutelySummary != "Light rain" {
+		t.Errorf("Expected to get [Light rain], but got [%s]", res.MinutelySummary)
+	}
+}
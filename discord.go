@@ -0,0 +1,243 @@
+package weatherline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// 1メッセージに含められる embed の最大数
+	discordEmbedsMax = 10
+
+	// 429 のときに再送する最大回数
+	discordRetryMax = 3
+)
+
+type discordError struct {
+	Status  int    `json:"-"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e discordError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Status, e.Message)
+}
+
+// discordRateLimit : 429 のレスポンス
+type discordRateLimit struct {
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"` // 秒
+	Global     bool    `json:"global"`
+}
+
+// 気象警報の embed の色
+const discordColorAlert = 0xE74C3C
+
+// embed の色
+var discordColors = map[Weather]int{
+	WeatherClearDay:          0xF1C40F,
+	WeatherClearNight:        0x2C3E50,
+	WeatherRain:              0x3498DB,
+	WeatherSnow:              0xECF0F1,
+	WeatherSleet:             0x95A5A6,
+	WeatherWind:              0x1ABC9C,
+	WeatherFog:               0xBDC3C7,
+	WeatherCloudy:            0x7F8C8D,
+	WeatherPartlyCloudyDay:   0xF39C12,
+	WeatherPartlyCloudyNight: 0x34495E,
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordPayload struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []discordEmbed `json:"embeds,omitempty"`
+}
+
+type discord struct {
+	url        string
+	httpClient *http.Client
+	sleep      func(time.Duration)
+}
+
+// NewDiscord : Create Notifier instance for Discord (Webhooks)
+func NewDiscord(webhookURL string) Notifier {
+	return &discord{
+		url:        webhookURL,
+		httpClient: &http.Client{},
+		sleep:      time.Sleep,
+	}
+}
+
+// embeds : 気象警報の embed、時間別予報の embed と日別予報の embed (1日1つ)
+//
+// 色は期間中で最も多い天気で決める
+func (n *discord) embeds(msg *Message) []discordEmbed {
+	f := msg.Format()
+	embeds := []discordEmbed{}
+
+	if len(msg.Alerts) > 0 {
+		lines := []string{}
+		for _, a := range msg.Alerts {
+			lines = append(lines, AlertText(a))
+		}
+
+		embeds = append(embeds, discordEmbed{
+			Title:       "Weather alert",
+			Description: strings.Join(lines, "\n"),
+			Color:       discordColorAlert,
+		})
+	}
+
+	if hourly := msg.HourlyPoints(); len(hourly) > 0 {
+		lines := []string{}
+		weathers := []Weather{}
+		for _, p := range hourly {
+			weather := WeatherText(p.Weather, p.Summary)
+
+			lines = append(lines, fmt.Sprintf("`%s` %s %s/%s %s",
+				p.Time.Format("15:04"),
+				weather,
+				f.Temperature(p.Temperature),
+				f.Temperature(p.ApparentTemperature),
+				f.Precip(p.Weather, p.PrecipProbability, p.PrecipAccumulation)))
+			weathers = append(weathers, p.Weather)
+		}
+
+		embeds = append(embeds, discordEmbed{
+			Title:       msg.Date.Format("01/02 (Mon)"),
+			Description: strings.Join(lines, "\n"),
+			Color:       discordColors[dominantWeather(weathers)],
+		})
+	}
+
+	for _, p := range msg.DailyPoints() {
+		if len(embeds) >= discordEmbedsMax {
+			break
+		}
+
+		embeds = append(embeds, discordEmbed{
+			Title:       fmt.Sprintf("%s %s", p.Time.Format("01/02 (Mon)"), IconText(p.Weather)),
+			Description: p.Summary,
+			Color:       discordColors[p.Weather],
+			Fields: []discordField{
+				{
+					Name:   "▲",
+					Value:  fmt.Sprintf("%s/%s (%s)", f.Temperature(p.TemperatureHigh), f.Temperature(p.ApparentTemperatureHigh), FormatTime("15:04", p.ApparentTemperatureHighTime)),
+					Inline: true,
+				},
+				{
+					Name:   "▼",
+					Value:  fmt.Sprintf("%s/%s (%s)", f.Temperature(p.TemperatureLow), f.Temperature(p.ApparentTemperatureLow), FormatTime("15:04", p.ApparentTemperatureLowTime)),
+					Inline: true,
+				},
+				{
+					Name:   "☂",
+					Value:  f.Precip(p.Weather, p.PrecipProbability, p.PrecipAccumulation),
+					Inline: true,
+				},
+			},
+		})
+	}
+
+	return embeds
+}
+
+// payload : リクエストボディ
+//
+// 予報がなければテキストを送る
+func (n *discord) payload(msg *Message) ([]byte, error) {
+	p := discordPayload{
+		Embeds: n.embeds(msg),
+	}
+	if len(p.Embeds) == 0 {
+		p.Content = strings.TrimSpace(msg.Text)
+	}
+
+	return json.Marshal(p)
+}
+
+// Notify : Notifier.Notify の実装
+//
+// 429 のときは retry_after だけ待って再送する
+func (n *discord) Notify(msg *Message) error {
+	body, err := n.payload(msg)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; ; i++ {
+		retryAfter, err := n.post(body)
+		if err == nil {
+			return nil
+		}
+		if retryAfter < 0 || i >= discordRetryMax {
+			return err
+		}
+
+		n.sleep(retryAfter)
+	}
+}
+
+// post : 429 の場合は再送までの時間を返す (再送できない場合は負の値)
+func (n *discord) post(body []byte) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return -1, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNoContent {
+		return 0, nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return -1, err
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		limit := discordRateLimit{}
+		if err := json.Unmarshal(b, &limit); err != nil || limit.RetryAfter <= 0 {
+			// ボディから取れなければ Retry-After ヘッダー (秒) を使う
+			limit.RetryAfter, _ = strconv.ParseFloat(res.Header.Get("Retry-After"), 64)
+		}
+
+		return time.Duration(limit.RetryAfter * float64(time.Second)), discordError{
+			Status:  res.StatusCode,
+			Message: limit.Message,
+		}
+	}
+
+	e := discordError{}
+	if err := json.Unmarshal(b, &e); err != nil {
+		e.Message = strings.TrimSpace(string(b))
+	}
+	e.Status = res.StatusCode
+
+	return -1, e
+}
@@ -0,0 +1,264 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiscordError_Error(t *testing.T) {
+	err := discordError{
+		Status:  404,
+		Code:    10015,
+		Message: "Unknown Webhook",
+	}
+
+	expected := "404: Unknown Webhook"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestNewDiscord(t *testing.T) {
+	webhookURL := "https://discord.com/api/webhooks/000/XXXX"
+
+	notifier := NewDiscord(webhookURL)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*discord)
+	if !ok {
+		t.Fatal("Expected discord instance, but not.")
+	}
+
+	if n.url != webhookURL {
+		t.Fatalf("Expected url is %s, but it's %s.", webhookURL, n.url)
+	}
+
+	if n.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+
+	if n.sleep == nil {
+		t.Fatal("sleep is nil")
+	}
+}
+
+func TestDiscord_payload(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		msg *Message
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+			},
+
+			expected: readFile("testdata/discord/payload00.json"),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: &Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Text: "\n01/31\n",
+			},
+
+			expected: "{\n  \"content\": \"01/31\"\n}\n",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			msg: alertMessage(tokyo),
+
+			expected: readFile("testdata/discord/payload01.json"),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			n := &discord{}
+			b, err := n.payload(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := indentJSON(b)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+type discordResponse struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+// discordFunc : responses を順に返す (使い切ったら最後のものを返し続ける)
+func discordFunc(responses []discordResponse) func(http.ResponseWriter, *http.Request) {
+	var mu sync.Mutex
+	count := 0
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			fmt.Fprint(w, `{"message": "405: Method Not Allowed", "code": 0}`)
+			return
+		}
+
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message": "Cannot send an empty message", "code": 50006}`)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil || !json.Valid(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message": "The request body contains invalid JSON.", "code": 50109}`)
+			return
+		}
+
+		mu.Lock()
+		res := responses[count]
+		if count < len(responses)-1 {
+			count++
+		}
+		mu.Unlock()
+
+		for k, v := range res.header {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(res.status)
+		fmt.Fprint(w, res.body)
+	}
+}
+
+func TestDiscord_Notify(t *testing.T) {
+	tests := []struct {
+		responses []discordResponse
+
+		expectedSleeps []time.Duration
+		expected       error
+	}{
+		// TEST0 {{{
+		{
+			responses: []discordResponse{
+				{status: http.StatusNoContent},
+			},
+
+			expectedSleeps: []time.Duration{},
+			expected:       nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			responses: []discordResponse{
+				{status: http.StatusNotFound, body: `{"message": "Unknown Webhook", "code": 10015}`},
+			},
+
+			expectedSleeps: []time.Duration{},
+			expected: discordError{
+				Status:  http.StatusNotFound,
+				Code:    10015,
+				Message: "Unknown Webhook",
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			responses: []discordResponse{
+				{status: http.StatusBadGateway, body: "Bad Gateway\n"},
+			},
+
+			expectedSleeps: []time.Duration{},
+			expected: discordError{
+				Status:  http.StatusBadGateway,
+				Message: "Bad Gateway",
+			},
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			responses: []discordResponse{
+				{status: http.StatusTooManyRequests, body: `{"message": "You are being rate limited.", "retry_after": 0.5, "global": false}`},
+				{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "2"}, body: `{"message": "You are being rate limited."}`},
+				{status: http.StatusNoContent},
+			},
+
+			expectedSleeps: []time.Duration{500 * time.Millisecond, 2 * time.Second},
+			expected:       nil,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			responses: []discordResponse{
+				{status: http.StatusTooManyRequests, body: `{"message": "You are being rate limited.", "retry_after": 1.25, "global": true}`},
+			},
+
+			expectedSleeps: []time.Duration{1250 * time.Millisecond, 1250 * time.Millisecond, 1250 * time.Millisecond},
+			expected: discordError{
+				Status:  http.StatusTooManyRequests,
+				Message: "You are being rate limited.",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(discordFunc(tt.responses)))
+			defer server.Close()
+
+			sleeps := []time.Duration{}
+			n := &discord{
+				url:        server.URL + "/api/webhooks/000/XXXX",
+				httpClient: server.Client(),
+				sleep: func(d time.Duration) {
+					sleeps = append(sleeps, d)
+				},
+			}
+
+			err := n.Notify(&Message{Text: "TEST"})
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err != tt.expected {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+
+			if !reflect.DeepEqual(sleeps, tt.expectedSleeps) {
+				t.Errorf("Expected to sleep %v, but slept %v", tt.expectedSleeps, sleeps)
+			}
+		})
+	}
+}
@@ -58,13 +58,19 @@ type Units int
 const (
 	UnitsUnknown Units = iota
 
-	UnitsUS // Imperial units (the default)
-	UnitsSI // SI units
+	UnitsUS   // Imperial units (the default)
+	UnitsSI   // SI units
+	UnitsCA   // same as si, except that windSpeed and windGust are in kilometers per hour
+	UnitsUK2  // same as si, except that nearestStormDistance and visibility are in miles, and windSpeed and windGust in miles per hour
+	UnitsAuto // automatically select units based on geographic location
 )
 
 var unitss = map[string]Units{
-	"us": UnitsUS,
-	"si": UnitsSI,
+	"us":   UnitsUS,
+	"si":   UnitsSI,
+	"ca":   UnitsCA,
+	"uk2":  UnitsUK2,
+	"auto": UnitsAuto,
 }
 
 func (u Units) String() string {
@@ -73,6 +79,12 @@ func (u Units) String() string {
 		return "us (Imperial units)"
 	case UnitsSI:
 		return "si (SI units)"
+	case UnitsCA:
+		return "ca (Canadian units)"
+	case UnitsUK2:
+		return "uk2 (British units)"
+	case UnitsAuto:
+		return "auto (Selected by location)"
 	default:
 		return "?? (Unknown)"
 	}
@@ -98,6 +110,78 @@ func UnitsValueOf(str string) Units {
 	return UnitsUnknown
 }
 
+// MarshalJSON : json.Marshal のための独自実装
+func (u Units) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.Value())
+}
+
+// UnmarshalJSON : json.Unmarshal のための独自実装
+func (u *Units) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	*u = UnitsValueOf(s)
+
+	return nil
+}
+
+// Block : Forecast API のデータブロック種別 (hourly、daily 以外)
+type Block int
+
+// Blocks
+const (
+	BlockUnknown Block = iota
+
+	BlockCurrently // 現在の天気
+	BlockMinutely  // 1時間先までの1分ごとの予報
+	BlockAlerts    // 気象警報
+	BlockFlags     // メタデータ (予報値の単位系など)
+)
+
+var blocks = map[string]Block{
+	"currently": BlockCurrently,
+	"minutely":  BlockMinutely,
+	"alerts":    BlockAlerts,
+	"flags":     BlockFlags,
+}
+
+func (b Block) String() string {
+	switch b {
+	case BlockCurrently:
+		return "currently (Current conditions)"
+	case BlockMinutely:
+		return "minutely (Minute-by-minute forecast for the next hour)"
+	case BlockAlerts:
+		return "alerts (Severe weather alerts)"
+	case BlockFlags:
+		return "flags (Metadata)"
+	default:
+		return "?? (Unknown)"
+	}
+}
+
+// Value : 値を返す
+func (b Block) Value() string {
+	for k, v := range blocks {
+		if v == b {
+			return k
+		}
+	}
+
+	return ""
+}
+
+// BlockValueOf : 文字列をBlock型に変換する
+func BlockValueOf(str string) Block {
+	if block, ok := blocks[str]; ok {
+		return block
+	}
+
+	return BlockUnknown
+}
+
 // Weather : 天気種別
 type Weather int
 
@@ -130,6 +214,22 @@ var weathers = map[string]Weather{
 	"partly-cloudy-night": WeatherPartlyCloudyNight,
 }
 
+// Value : 値を返す (API の icon の値)
+func (w Weather) Value() string {
+	for k, v := range weathers {
+		if v == w {
+			return k
+		}
+	}
+
+	return ""
+}
+
+// MarshalJSON : json.Marshal のための独自実装
+func (w Weather) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.Value())
+}
+
 // UnmarshalJSON : json.Unmarshal のための独自実装
 func (w *Weather) UnmarshalJSON(b []byte) error {
 	var s string
@@ -146,3 +246,104 @@ func (w *Weather) UnmarshalJSON(b []byte) error {
 
 	return nil
 }
+
+// night : 夜間の天気に変換する
+func (w Weather) night() Weather {
+	switch w {
+	case WeatherClearDay:
+		return WeatherClearNight
+	case WeatherPartlyCloudyDay:
+		return WeatherPartlyCloudyNight
+	default:
+		return w
+	}
+}
+
+// Icon : 天気種別を表す絵文字
+type Icon rune
+
+// Icons
+const (
+	IconUnknown Icon = 0x2753
+
+	IconClearDay          Icon = 0x2600
+	IconClearNight        Icon = 0x2600
+	IconRain              Icon = 0x2614
+	IconSnow              Icon = 0x2744
+	IconSleet             Icon = 0x2614
+	IconWind              Icon = 0x1f343
+	IconFog               Icon = 0x1f32b
+	IconCloudy            Icon = 0x2601
+	IconPartlyCloudyDay   Icon = 0x26c5
+	IconPartlyCloudyNight Icon = 0x26c5
+)
+
+var icons = map[Weather]Icon{
+	WeatherClearDay:          IconClearDay,
+	WeatherClearNight:        IconClearNight,
+	WeatherRain:              IconRain,
+	WeatherSnow:              IconSnow,
+	WeatherSleet:             IconSleet,
+	WeatherWind:              IconWind,
+	WeatherFog:               IconFog,
+	WeatherCloudy:            IconCloudy,
+	WeatherPartlyCloudyDay:   IconPartlyCloudyDay,
+	WeatherPartlyCloudyNight: IconPartlyCloudyNight,
+}
+
+// Icon : 天気種別の絵文字を返す (天気種別が不明な場合は false)
+func (w Weather) Icon() (Icon, bool) {
+	icon, ok := icons[w]
+	return icon, ok
+}
+
+// SMTPSecurity : SMTP 接続の暗号化方式
+type SMTPSecurity int
+
+// SMTP securities
+const (
+	SMTPSecurityUnknown SMTPSecurity = iota
+
+	SMTPSecurityNone     // Plain text
+	SMTPSecurityStartTLS // STARTTLS (the default)
+	SMTPSecurityTLS      // Implicit TLS (SMTPS)
+)
+
+var smtpSecurities = map[string]SMTPSecurity{
+	"none":     SMTPSecurityNone,
+	"starttls": SMTPSecurityStartTLS,
+	"tls":      SMTPSecurityTLS,
+}
+
+func (s SMTPSecurity) String() string {
+	switch s {
+	case SMTPSecurityNone:
+		return "none (Plain text)"
+	case SMTPSecurityStartTLS:
+		return "starttls (STARTTLS)"
+	case SMTPSecurityTLS:
+		return "tls (Implicit TLS)"
+	default:
+		return "?? (Unknown)"
+	}
+}
+
+// Value : 値を返す
+func (s SMTPSecurity) Value() string {
+	for k, v := range smtpSecurities {
+		if v == s {
+			return k
+		}
+	}
+
+	return ""
+}
+
+// SMTPSecurityValueOf : 文字列をSMTPSecurity型に変換する
+func SMTPSecurityValueOf(str string) SMTPSecurity {
+	if security, ok := smtpSecurities[str]; ok {
+		return security
+	}
+
+	return SMTPSecurityUnknown
+}
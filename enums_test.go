@@ -142,6 +142,24 @@ func TestUnits_String(t *testing.T) {
 			expected: "?? (Unknown)",
 		},
 		// }}}
+		// TEST3 {{{
+		{
+			u:        UnitsCA,
+			expected: "ca (Canadian units)",
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			u:        UnitsUK2,
+			expected: "uk2 (British units)",
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			u:        UnitsAuto,
+			expected: "auto (Selected by location)",
+		},
+		// }}}
 	}
 
 	for i, tt := range tests {
@@ -180,6 +198,12 @@ func TestUnits_Value(t *testing.T) {
 			expected: "",
 		},
 		// }}}
+		// TEST3 {{{
+		{
+			u:        UnitsUK2,
+			expected: "uk2",
+		},
+		// }}}
 	}
 
 	for i, tt := range tests {
@@ -218,6 +242,18 @@ func TestUnitsValueOf(t *testing.T) {
 			expected: UnitsUnknown,
 		},
 		// }}}
+		// TEST3 {{{
+		{
+			s:        "auto",
+			expected: UnitsAuto,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			s:        "ca",
+			expected: UnitsCA,
+		},
+		// }}}
 	}
 
 	for i, tt := range tests {
@@ -233,6 +269,187 @@ func TestUnitsValueOf(t *testing.T) {
 	}
 }
 
+func TestUnits_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json []byte
+
+		expected    Units
+		expectError bool
+	}{
+		// TEST0 {{{
+		{
+			json:        []byte(`"ca"`),
+			expected:    UnitsCA,
+			expectError: false,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			json:        []byte(`"unknown-units"`),
+			expected:    UnitsUnknown,
+			expectError: false,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			json:        []byte(`1`),
+			expectError: true,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			var u Units
+			err := u.UnmarshalJSON(tt.json)
+			if err != nil {
+				if !tt.expectError {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				}
+				return
+			}
+
+			if tt.expectError {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+				return
+			}
+
+			if u != tt.expected {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected, u)
+			}
+		})
+	}
+}
+
+func TestBlock_String(t *testing.T) {
+	tests := []struct {
+		b        Block
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			b:        BlockCurrently,
+			expected: "currently (Current conditions)",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			b:        BlockMinutely,
+			expected: "minutely (Minute-by-minute forecast for the next hour)",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			b:        BlockAlerts,
+			expected: "alerts (Severe weather alerts)",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			b:        BlockFlags,
+			expected: "flags (Metadata)",
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			b:        BlockUnknown,
+			expected: "?? (Unknown)",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.b.String()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBlock_Value(t *testing.T) {
+	tests := []struct {
+		b        Block
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			b:        BlockCurrently,
+			expected: "currently",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			b:        BlockFlags,
+			expected: "flags",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			b:        BlockUnknown,
+			expected: "",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.b.Value()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBlockValueOf(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected Block
+	}{
+		// TEST0 {{{
+		{
+			s:        "minutely",
+			expected: BlockMinutely,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			s:        "alerts",
+			expected: BlockAlerts,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			s:        "hourly",
+			expected: BlockUnknown,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := BlockValueOf(tt.s)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
 func TestWeather_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
 		json []byte
@@ -287,3 +504,173 @@ func TestWeather_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestWeather_Icon(t *testing.T) {
+	tests := []struct {
+		w Weather
+
+		expected   Icon
+		expectedOK bool
+	}{
+		// TEST0 {{{
+		{
+			w:          WeatherSnow,
+			expected:   IconSnow,
+			expectedOK: true,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			w:          WeatherUnknown,
+			expectedOK: false,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual, ok := tt.w.Icon()
+			if ok != tt.expectedOK {
+				t.Errorf("Expected ok to be %v, but got %v", tt.expectedOK, ok)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%c], but got [%c]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSMTPSecurity_String(t *testing.T) {
+	tests := []struct {
+		s        SMTPSecurity
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			s:        SMTPSecurityNone,
+			expected: "none (Plain text)",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			s:        SMTPSecurityStartTLS,
+			expected: "starttls (STARTTLS)",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			s:        SMTPSecurityTLS,
+			expected: "tls (Implicit TLS)",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			s:        SMTPSecurityUnknown,
+			expected: "?? (Unknown)",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.s.String()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSMTPSecurity_Value(t *testing.T) {
+	tests := []struct {
+		s        SMTPSecurity
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			s:        SMTPSecurityNone,
+			expected: "none",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			s:        SMTPSecurityStartTLS,
+			expected: "starttls",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			s:        SMTPSecurityTLS,
+			expected: "tls",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			s:        SMTPSecurityUnknown,
+			expected: "",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.s.Value()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSMTPSecurityValueOf(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected SMTPSecurity
+	}{
+		// TEST0 {{{
+		{
+			s:        "none",
+			expected: SMTPSecurityNone,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			s:        "starttls",
+			expected: SMTPSecurityStartTLS,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			s:        "tls",
+			expected: SMTPSecurityTLS,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			s:        "",
+			expected: SMTPSecurityUnknown,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := SMTPSecurityValueOf(tt.s)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package weatherline
+
+import (
+	"math"
+	"time"
+)
+
+// Forecast : 天気予報 API client interface
+type Forecast interface {
+	Get(Lang, Units, ...GetOption) (*Report, error)
+}
+
+// GetOption : Forecast.Get のオプション
+type GetOption func(*getOptions)
+
+type getOptions struct {
+	blocks       []Block
+	extendHourly bool
+}
+
+// WithBlocks : 時間別・日別予報のほかに取得するデータブロックを指定する
+//
+// 指定しない場合は alerts と flags を取得する。
+// データブロックに対応していないプロバイダでは無視する。
+func WithBlocks(blocks ...Block) GetOption {
+	return func(o *getOptions) {
+		o.blocks = blocks
+	}
+}
+
+// WithExtendHourly : 時間別予報を48時間から168時間に延長する
+//
+// 延長に対応していないプロバイダでは無視する。
+func WithExtendHourly() GetOption {
+	return func(o *getOptions) {
+		o.extendHourly = true
+	}
+}
+
+func newGetOptions(opts []GetOption) getOptions {
+	o := getOptions{
+		blocks: []Block{BlockAlerts, BlockFlags},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// has : データブロックを取得するかどうか
+func (o getOptions) has(b Block) bool {
+	for _, block := range o.blocks {
+		if block == b {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Report : 天気予報 API に依存しない天気情報
+//
+// API から値が得られない項目は NaN になる。
+type Report struct {
+	Location        *time.Location // 予報地点のタイムゾーン
+	Units           Units          // 予報値の単位系
+	Currently       *HourlyPoint   // 現在の天気 (BlockCurrently を取得した場合のみ)
+	Minutely        []MinutelyPoint
+	MinutelySummary string // 1時間先までの降水の概要 (BlockMinutely を取得した場合のみ)
+	Hourly          []HourlyPoint
+	Daily           []DailyPoint
+	Alerts          []Alert          // 発表中の気象警報 (BlockAlerts を取得した場合のみ)
+	Sources         []string         // 予報に使ったプロバイダ (複数のプロバイダをまとめた場合のみ)
+	Failures        map[string]error // 予報を取得できなかったプロバイダとそのエラー (複数のプロバイダをまとめた場合のみ)
+}
+
+// Alert : 気象警報・注意報
+type Alert struct {
+	Title       string
+	Severity    string    // 重大度 (advisory, watch, warning)
+	Regions     []string  // 対象の地域
+	Time        time.Time // 発表時刻
+	Expires     time.Time // 失効時刻 (不明な場合はゼロ値)
+	Description string
+	URI         string // 詳細情報の URL
+}
+
+// ActiveIn : from から to までの期間に有効かどうか
+func (a *Alert) ActiveIn(from, to time.Time) bool {
+	if !a.Time.IsZero() && !a.Time.Before(to) {
+		return false
+	}
+
+	return a.Expires.IsZero() || a.Expires.After(from)
+}
+
+// MinutelyPoint : 1分ごとの降水予報
+type MinutelyPoint struct {
+	Time              time.Time
+	PrecipIntensity   float64 // 降水強度 (mm/h、us は in/h)
+	PrecipProbability float64 // 降水確率 (0-1)
+	PrecipType        string  // 降水の種類 (rain, snow, sleet)、降水がなければ空文字
+}
+
+// HourlyPoint : 1時間ごとの天気情報
+type HourlyPoint struct {
+	Time                time.Time
+	Weather             Weather
+	Summary             string
+	Temperature         float64
+	ApparentTemperature float64     // 体感気温
+	PrecipProbability   float64     // 降水確率 (0-1)
+	PrecipAccumulation  float64     // 積雪量、天気が雪でなければ無視
+	Conditions          *Conditions // その他の気象要素 (API から得られない場合は nil)
+}
+
+// DailyPoint : 1日ごとの天気情報
+type DailyPoint struct {
+	Time                        time.Time
+	Weather                     Weather
+	Summary                     string
+	TemperatureHigh             float64
+	TemperatureHighTime         time.Time
+	TemperatureLow              float64
+	TemperatureLowTime          time.Time
+	ApparentTemperatureHigh     float64 // 最高体感気温
+	ApparentTemperatureHighTime time.Time
+	ApparentTemperatureLow      float64 // 最低体感気温
+	ApparentTemperatureLowTime  time.Time
+	PrecipProbability           float64          // 降水確率 (0-1)
+	PrecipAccumulation          float64          // 積雪量、天気が雪でなければ無視
+	Conditions                  *DailyConditions // その他の気象要素 (API から得られない場合は nil)
+}
+
+// Conditions : 気温・降水確率以外の気象要素
+//
+// 単位は Report.Units の単位系に従う。
+type Conditions struct {
+	PrecipIntensity float64 // 降水強度 (mm/h、us は in/h)
+	PrecipType      string  // 降水の種類 (rain, snow, sleet)、降水がなければ空文字
+	DewPoint        float64 // 露点
+	Humidity        float64 // 湿度 (0-1)
+	Pressure        float64 // 海面気圧 (hPa)
+	WindSpeed       float64 // 風速 (m/s、ca は km/h、us と uk2 は mph)
+	WindGust        float64 // 最大瞬間風速
+	WindBearing     float64 // 風向 (北を 0 とした時計回りの角度)
+	CloudCover      float64 // 雲量 (0-1)
+	UVIndex         float64 // UV 指数
+	Visibility      float64 // 視程 (km、us と uk2 はマイル)
+	Ozone           float64 // オゾン全量 (DU)
+}
+
+// DailyConditions : 日別予報の気温・降水確率以外の気象要素
+type DailyConditions struct {
+	Conditions
+	SunriseTime        time.Time
+	SunsetTime         time.Time
+	MoonPhase          float64 // 月相 (0 が新月、0.5 が満月)
+	PrecipIntensityMax float64 // 最大降水強度
+}
+
+// peak : 期間内の時間別予報から最高値・最低値とその時刻を求める
+func peak(hourly []HourlyPoint, from, to time.Time, value func(HourlyPoint) float64) (high float64, highTime time.Time, low float64, lowTime time.Time) {
+	high, low = math.NaN(), math.NaN()
+	for _, p := range hourly {
+		if p.Time.Before(from) || !p.Time.Before(to) {
+			continue
+		}
+
+		v := value(p)
+		if math.IsNaN(v) {
+			continue
+		}
+		if math.IsNaN(high) || v > high {
+			high, highTime = v, p.Time
+		}
+		if math.IsNaN(low) || v < low {
+			low, lowTime = v, p.Time
+		}
+	}
+
+	return high, highTime, low, lowTime
+}
+
+// fahrenheit : 摂氏を華氏に変換する
+func fahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// apparentTemperature : 気温 (℃)、湿度 (%)、風速 (m/s) から体感気温 (℃) を求める
+//
+// Australian Bureau of Meteorology の計算式を用いる
+func apparentTemperature(t, humidity, windSpeed float64) float64 {
+	e := humidity / 100 * 6.105 * math.Exp(17.27*t/(237.7+t))
+	return t + 0.33*e - 0.70*windSpeed - 4.00
+}
+
+// 多数決で同数の場合に優先する天気
+var weatherPriorities = []Weather{
+	WeatherSnow,
+	WeatherSleet,
+	WeatherRain,
+	WeatherFog,
+	WeatherWind,
+	WeatherCloudy,
+	WeatherPartlyCloudyDay,
+	WeatherPartlyCloudyNight,
+	WeatherClearDay,
+	WeatherClearNight,
+}
+
+// dominantWeather : 最も多い天気を返す
+func dominantWeather(weathers []Weather) Weather {
+	counts := map[Weather]int{}
+	for _, w := range weathers {
+		counts[w]++
+	}
+
+	dominant, max := WeatherUnknown, 0
+	for _, w := range weatherPriorities {
+		if counts[w] > max {
+			dominant, max = w, counts[w]
+		}
+	}
+
+	return dominant
+}
+
+// dailyFromHourly : 時間別予報を日ごとにまとめて日別予報を作る
+func dailyFromHourly(hourly []HourlyPoint) []DailyPoint {
+	var daily []DailyPoint
+	for i := 0; i < len(hourly); {
+		t := hourly[i].Time
+		from := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		to := from.AddDate(0, 0, 1)
+
+		j := i
+		for j < len(hourly) && hourly[j].Time.Before(to) {
+			j++
+		}
+		points := hourly[i:j]
+		i = j
+
+		d := DailyPoint{
+			Time:               from,
+			PrecipProbability:  math.NaN(),
+			PrecipAccumulation: math.NaN(),
+		}
+		d.TemperatureHigh, d.TemperatureHighTime, d.TemperatureLow, d.TemperatureLowTime = peak(points, from, to, func(p HourlyPoint) float64 {
+			return p.Temperature
+		})
+		d.ApparentTemperatureHigh, d.ApparentTemperatureHighTime, d.ApparentTemperatureLow, d.ApparentTemperatureLowTime = peak(points, from, to, func(p HourlyPoint) float64 {
+			return p.ApparentTemperature
+		})
+
+		var weathers []Weather
+		for _, p := range points {
+			weathers = append(weathers, p.Weather)
+			if !math.IsNaN(p.PrecipProbability) && (math.IsNaN(d.PrecipProbability) || p.PrecipProbability > d.PrecipProbability) {
+				d.PrecipProbability = p.PrecipProbability
+			}
+			if !math.IsNaN(p.PrecipAccumulation) {
+				if math.IsNaN(d.PrecipAccumulation) {
+					d.PrecipAccumulation = 0
+				}
+				d.PrecipAccumulation += p.PrecipAccumulation
+			}
+		}
+		d.Weather = dominantWeather(weathers)
+		for _, p := range points {
+			if p.Weather == d.Weather {
+				d.Summary = p.Summary
+				break
+			}
+		}
+
+		daily = append(daily, d)
+	}
+
+	return daily
+}
+
+// inches : センチメートルをインチに変換する
+func inches(cm float64) float64 {
+	return cm / 2.54
+}
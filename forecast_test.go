@@ -0,0 +1,247 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sameValue : NaN を等しいとみなして比較する
+func sameValue(a, b interface{}) bool {
+	return fmt.Sprintf("%+v", a) == fmt.Sprintf("%+v", b)
+}
+
+// sameDeep : reflect.DeepEqual と同様に比較するが、NaN 同士は等しいとみなす
+func sameDeep(a, b interface{}) bool {
+	return sameDeepValue(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func sameDeepValue(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+		return x == y || math.IsNaN(x) && math.IsNaN(y)
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return sameDeepValue(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !sameDeepValue(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() {
+			return false
+		}
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !sameDeepValue(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for _, k := range a.MapKeys() {
+			v := b.MapIndex(k)
+			if !v.IsValid() || !sameDeepValue(a.MapIndex(k), v) {
+				return false
+			}
+		}
+		return true
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.String:
+		return a.String() == b.String()
+	default:
+		return a.Pointer() == b.Pointer()
+	}
+}
+
+func TestPeak(t *testing.T) {
+	base := time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC)
+	hourly := []HourlyPoint{
+		{Time: base.Add(-1 * time.Hour), Temperature: 20},
+		{Time: base, Temperature: 1.5},
+		{Time: base.Add(1 * time.Hour), Temperature: -0.5},
+		{Time: base.Add(2 * time.Hour), Temperature: math.NaN()},
+		{Time: base.Add(3 * time.Hour), Temperature: 8.5},
+		{Time: base.Add(4 * time.Hour), Temperature: 8.5},
+		{Time: base.Add(24 * time.Hour), Temperature: -10},
+	}
+
+	tests := []struct {
+		from time.Time
+		to   time.Time
+
+		expectedHigh     float64
+		expectedHighTime time.Time
+		expectedLow      float64
+		expectedLowTime  time.Time
+	}{
+		// TEST0 {{{
+		{
+			from: base,
+			to:   base.AddDate(0, 0, 1),
+
+			expectedHigh:     8.5,
+			expectedHighTime: base.Add(3 * time.Hour),
+			expectedLow:      -0.5,
+			expectedLowTime:  base.Add(1 * time.Hour),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			from: base.Add(2 * time.Hour),
+			to:   base.Add(3 * time.Hour),
+
+			expectedHigh: math.NaN(),
+			expectedLow:  math.NaN(),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			high, highTime, low, lowTime := peak(hourly, tt.from, tt.to, func(p HourlyPoint) float64 {
+				return p.Temperature
+			})
+			if !sameValue(high, tt.expectedHigh) || !highTime.Equal(tt.expectedHighTime) {
+				t.Errorf("Expected to get [%v %v], but got [%v %v]", tt.expectedHigh, tt.expectedHighTime, high, highTime)
+			}
+			if !sameValue(low, tt.expectedLow) || !lowTime.Equal(tt.expectedLowTime) {
+				t.Errorf("Expected to get [%v %v], but got [%v %v]", tt.expectedLow, tt.expectedLowTime, low, lowTime)
+			}
+		})
+	}
+}
+
+func TestApparentTemperature(t *testing.T) {
+	// e = 0.5 * 6.105 * exp(17.27 * 20 / 257.7) = 11.662...
+	expected := 18.448
+
+	actual := apparentTemperature(20, 50, 2)
+	if math.Abs(actual-expected) > 0.01 {
+		t.Errorf("Expected to get [%f], but got [%f]", expected, actual)
+	}
+}
+
+func TestDominantWeather(t *testing.T) {
+	tests := []struct {
+		weathers []Weather
+		expected Weather
+	}{
+		// TEST0 {{{
+		{
+			weathers: nil,
+			expected: WeatherUnknown,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			weathers: []Weather{WeatherClearDay, WeatherCloudy, WeatherClearDay},
+			expected: WeatherClearDay,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			weathers: []Weather{WeatherClearDay, WeatherRain, WeatherClearDay, WeatherRain},
+			expected: WeatherRain,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			weathers: []Weather{WeatherUnknown, WeatherUnknown, WeatherFog},
+			expected: WeatherFog,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := dominantWeather(tt.weathers)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAlert_ActiveIn(t *testing.T) {
+	from := time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	tests := []struct {
+		alert Alert
+
+		expected bool
+	}{
+		// TEST0 {{{
+		{
+			alert: Alert{Time: from.Add(-3 * time.Hour), Expires: from.Add(6 * time.Hour)},
+
+			expected: true,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			alert: Alert{Time: from.Add(-6 * time.Hour), Expires: from},
+
+			expected: false,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			alert: Alert{Time: to},
+
+			expected: false,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			alert: Alert{Time: from.Add(-24 * time.Hour)},
+
+			expected: true,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.alert.ActiveIn(from, to)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
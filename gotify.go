@@ -0,0 +1,117 @@
+package weatherline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Gotify の優先度 (0 〜 10)
+var gotifyPriorities = map[pushLevel]int{
+	pushLevelDefault: 5,
+	pushLevelHigh:    8,
+}
+
+type gotifyError struct {
+	Code        int    `json:"errorCode"`
+	Message     string `json:"error"`
+	Description string `json:"errorDescription"`
+}
+
+func (e gotifyError) Error() string {
+	msg := e.Message
+	if e.Description != "" {
+		msg = e.Description
+	}
+
+	return fmt.Sprintf("%d: %s", e.Code, msg)
+}
+
+type gotifyRequest struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+type gotify struct {
+	token     string
+	threshold float64
+
+	url        string
+	httpClient *http.Client
+}
+
+// NewGotify : Create Notifier instance for Gotify
+//
+// token にはアプリケーションのトークンを指定する。
+// 降水確率が threshold 以上の時間があるか雪が降る場合は優先度を上げる。
+func NewGotify(serverURL, token string, threshold float64) Notifier {
+	return &gotify{
+		token:     token,
+		threshold: threshold,
+
+		url:        serverURL,
+		httpClient: &http.Client{},
+	}
+}
+
+// payload : リクエストボディ
+func (n *gotify) payload(msg *Message) ([]byte, error) {
+	level, _ := pushAlert(msg, n.threshold)
+
+	return json.Marshal(gotifyRequest{
+		Title:    msg.Title(),
+		Message:  strings.TrimSpace(msg.Text),
+		Priority: gotifyPriorities[level],
+	})
+}
+
+// Notify : Notifier.Notify の実装
+func (n *gotify) Notify(msg *Message) error {
+	body, err := n.payload(msg)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(n.url)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "message")
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", n.token)
+
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	response := gotifyError{}
+	if err := json.Unmarshal(b, &response); err != nil || response.Message == "" {
+		response.Message = strings.TrimSpace(string(b))
+	}
+	response.Code = res.StatusCode
+
+	return response
+}
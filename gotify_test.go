@@ -0,0 +1,257 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGotifyError_Error(t *testing.T) {
+	tests := []struct {
+		err gotifyError
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			err: gotifyError{
+				Code:        401,
+				Message:     "Unauthorized",
+				Description: "you need to provide a valid access token or user credentials to access this api",
+			},
+
+			expected: "401: you need to provide a valid access token or user credentials to access this api",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			err: gotifyError{
+				Code:    502,
+				Message: "Bad Gateway",
+			},
+
+			expected: "502: Bad Gateway",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.err.Error()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewGotify(t *testing.T) {
+	serverURL := "https://gotify.example.com"
+	token := "AXXXXXXXXXXXXXX"
+	threshold := 0.6
+
+	notifier := NewGotify(serverURL, token, threshold)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*gotify)
+	if !ok {
+		t.Fatal("Expected gotify instance, but not.")
+	}
+
+	if n.url != serverURL {
+		t.Fatalf("Expected url is %s, but it's %s.", serverURL, n.url)
+	}
+
+	if n.token != token {
+		t.Fatalf("Expected token is %s, but it's %s.", token, n.token)
+	}
+
+	if n.threshold != threshold {
+		t.Fatalf("Expected threshold is %f, but it's %f.", threshold, n.threshold)
+	}
+
+	if n.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+}
+
+func TestGotify_payload(t *testing.T) {
+	date := time.Date(2018, 1, 31, 0, 0, 0, 0, loadLocation("Asia/Tokyo"))
+
+	tests := []struct {
+		msg *Message
+
+		expected gotifyRequest
+	}{
+		// TEST0 {{{
+		{
+			msg: &Message{
+				Date: date,
+				Text: "\n01/31\n",
+				Report: &Report{
+					Hourly: []HourlyPoint{
+						{Time: date.Add(9 * time.Hour), Weather: WeatherCloudy, PrecipProbability: 0.1},
+					},
+				},
+			},
+
+			expected: gotifyRequest{
+				Title:    "Weather forecast 01/31 (Wed)",
+				Message:  "01/31",
+				Priority: 5,
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: &Message{
+				Date: date,
+				Text: "\n01/31\n",
+				Report: &Report{
+					Hourly: []HourlyPoint{
+						{Time: date.Add(9 * time.Hour), Weather: WeatherRain, PrecipProbability: 0.7},
+					},
+				},
+			},
+
+			expected: gotifyRequest{
+				Title:    "Weather forecast 01/31 (Wed)",
+				Message:  "01/31",
+				Priority: 8,
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			n := &gotify{threshold: DefaultPushThreshold}
+			b, err := n.payload(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := gotifyRequest{}
+			if err := json.Unmarshal(b, &actual); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected to get %+v, but got %+v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func gotifyFunc(token string, resStatus int, resBody string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/gotify/message" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Not Found","errorCode":404,"errorDescription":"page not found"}`)
+			return
+		}
+
+		if r.Header.Get("X-Gotify-Key") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"Unauthorized","errorCode":401,"errorDescription":"you need to provide a valid access token or user credentials to access this api"}`)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil || !json.Valid(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"Bad Request","errorCode":400,"errorDescription":"invalid JSON"}`)
+			return
+		}
+
+		w.WriteHeader(resStatus)
+		fmt.Fprint(w, resBody)
+	}
+}
+
+func TestGotify_Notify(t *testing.T) {
+	tests := []struct {
+		token     string
+		resStatus int
+		resBody   string
+
+		expected error
+	}{
+		// TEST0 {{{
+		{
+			token:     "AXXXXXXXXXXXXXX",
+			resStatus: http.StatusOK,
+			resBody:   `{"id":1,"appid":1,"message":"TEST"}`,
+
+			expected: nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			token:     "AYYYYYYYYYYYYYY",
+			resStatus: http.StatusOK,
+			resBody:   `{"id":1,"appid":1,"message":"TEST"}`,
+
+			expected: gotifyError{
+				Code:        http.StatusUnauthorized,
+				Message:     "Unauthorized",
+				Description: "you need to provide a valid access token or user credentials to access this api",
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			token:     "AXXXXXXXXXXXXXX",
+			resStatus: http.StatusBadGateway,
+			resBody:   "Bad Gateway\n",
+
+			expected: gotifyError{
+				Code:    http.StatusBadGateway,
+				Message: "Bad Gateway",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(gotifyFunc("AXXXXXXXXXXXXXX", tt.resStatus, tt.resBody)))
+			defer server.Close()
+
+			n := &gotify{
+				token:     tt.token,
+				threshold: DefaultPushThreshold,
+
+				url:        server.URL + "/gotify",
+				httpClient: server.Client(),
+			}
+
+			err := n.Notify(&Message{Text: "TEST"})
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err != tt.expected {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+		})
+	}
+}
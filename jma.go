@@ -0,0 +1,283 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	jmaAPIBase = "https://www.jma.go.jp"
+)
+
+type jmaError struct {
+	Code    int
+	Message string
+}
+
+func (e jmaError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+// 天気コードの百の位 (1:晴れ, 2:曇り, 3:雨, 4:雪) で判定できないもの
+var jmaWeathers = map[int]Weather{
+	101: WeatherPartlyCloudyDay, // 晴時々曇
+	110: WeatherPartlyCloudyDay, // 晴後時々曇
+	111: WeatherPartlyCloudyDay, // 晴後曇
+	132: WeatherPartlyCloudyDay, // 晴朝夕曇
+	201: WeatherPartlyCloudyDay, // 曇時々晴
+	209: WeatherFog,             // 霧
+	210: WeatherPartlyCloudyDay, // 曇後時々晴
+	211: WeatherPartlyCloudyDay, // 曇後晴
+	223: WeatherPartlyCloudyDay, // 曇日中時々晴
+	303: WeatherSleet,           // 雨時々雪
+	304: WeatherSleet,           // 雨か雪
+	309: WeatherSleet,           // 雨一時雪
+	314: WeatherSleet,           // 雨後時々雪
+	315: WeatherSleet,           // 雨後雪
+	322: WeatherSleet,           // 雨朝晩一時雪
+	326: WeatherSleet,           // 雨夕方から雪
+	327: WeatherSleet,           // 雨夜は雪
+	329: WeatherSleet,           // 雨一時みぞれ
+	340: WeatherSleet,           // 雪か雨
+	403: WeatherSleet,           // 雪時々雨
+	409: WeatherSleet,           // 雪一時雨
+	414: WeatherSleet,           // 雪後雨
+	422: WeatherSleet,           // 雪昼頃から雨
+	423: WeatherSleet,           // 雪夕方から雨
+	426: WeatherSleet,           // 雪後みぞれ
+	427: WeatherSleet,           // 雪一時みぞれ
+}
+
+// jmaWeather : 気象庁の天気コードを天気種別に変換する
+func jmaWeather(code string) Weather {
+	c, err := strconv.Atoi(code)
+	if err != nil {
+		return WeatherUnknown
+	}
+
+	if w, ok := jmaWeathers[c]; ok {
+		return w
+	}
+
+	switch c / 100 {
+	case 1:
+		return WeatherClearDay
+	case 2:
+		return WeatherCloudy
+	case 3:
+		return WeatherRain
+	case 4:
+		return WeatherSnow
+	default:
+		return WeatherUnknown
+	}
+}
+
+// jmaValue : 空文字は NaN として値を返す
+func jmaValue(values []string, i int) float64 {
+	if i >= len(values) {
+		return math.NaN()
+	}
+
+	v, err := strconv.ParseFloat(values[i], 64)
+	if err != nil {
+		return math.NaN()
+	}
+
+	return v
+}
+
+type jmaArea struct {
+	Area struct {
+		Name string `json:"name"`
+		Code string `json:"code"`
+	} `json:"area"`
+	WeatherCodes []string `json:"weatherCodes"`
+	Weathers     []string `json:"weathers"`
+	Pops         []string `json:"pops"`     // 降水確率 (%)
+	Temps        []string `json:"temps"`    // 0時は朝の最低気温、9時は日中の最高気温
+	TempsMin     []string `json:"tempsMin"` // 週間予報の最低気温
+	TempsMax     []string `json:"tempsMax"` // 週間予報の最高気温
+}
+
+type jmaTimeSeries struct {
+	TimeDefines []time.Time `json:"timeDefines"`
+	Areas       []jmaArea   `json:"areas"`
+}
+
+// jmaResponse : 短期予報と週間予報
+type jmaResponse []struct {
+	TimeSeries []jmaTimeSeries `json:"timeSeries"`
+}
+
+func (r jmaResponse) report(units Units) *Report {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		loc = time.FixedZone("JST", 9*60*60)
+	}
+
+	temperature := func(c float64) float64 {
+		if units == UnitsUS {
+			return fahrenheit(c)
+		}
+		return c
+	}
+
+	var daily []*DailyPoint
+	days := map[string]*DailyPoint{}
+	day := func(t time.Time) *DailyPoint {
+		t = t.In(loc)
+		key := t.Format("20060102")
+		if d, ok := days[key]; ok {
+			return d
+		}
+
+		d := &DailyPoint{
+			Time:                    time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc),
+			TemperatureHigh:         math.NaN(),
+			TemperatureLow:          math.NaN(),
+			ApparentTemperatureHigh: math.NaN(),
+			ApparentTemperatureLow:  math.NaN(),
+			PrecipProbability:       math.NaN(),
+			PrecipAccumulation:      math.NaN(),
+		}
+		days[key] = d
+		daily = append(daily, d)
+
+		return d
+	}
+
+	// 短期予報を優先し、週間予報で補う
+	var pops []HourlyPoint
+	for _, forecast := range r {
+		for _, series := range forecast.TimeSeries {
+			if len(series.Areas) == 0 {
+				continue
+			}
+
+			area := series.Areas[0]
+			for i, t := range series.TimeDefines {
+				d := day(t)
+
+				if i < len(area.WeatherCodes) && d.Weather == WeatherUnknown {
+					d.Weather = jmaWeather(area.WeatherCodes[i])
+					if i < len(area.Weathers) {
+						d.Summary = strings.Join(strings.Fields(area.Weathers[i]), "")
+					}
+				}
+
+				if pop := jmaValue(area.Pops, i) / 100; !math.IsNaN(pop) {
+					if len(area.WeatherCodes) == 0 {
+						// 短期予報の6時間ごとの降水確率
+						pops = append(pops, HourlyPoint{
+							Time:              t.In(loc),
+							PrecipProbability: pop,
+						})
+						if math.IsNaN(d.PrecipProbability) || pop > d.PrecipProbability {
+							d.PrecipProbability = pop
+						}
+					} else if math.IsNaN(d.PrecipProbability) {
+						d.PrecipProbability = pop
+					}
+				}
+
+				if v := jmaValue(area.Temps, i); !math.IsNaN(v) {
+					if t.In(loc).Hour() == 0 {
+						d.TemperatureLow = temperature(v)
+					} else {
+						d.TemperatureHigh = temperature(v)
+					}
+				}
+				if v := jmaValue(area.TempsMin, i); !math.IsNaN(v) && math.IsNaN(d.TemperatureLow) {
+					d.TemperatureLow = temperature(v)
+				}
+				if v := jmaValue(area.TempsMax, i); !math.IsNaN(v) && math.IsNaN(d.TemperatureHigh) {
+					d.TemperatureHigh = temperature(v)
+				}
+			}
+		}
+	}
+
+	report := &Report{
+		Location: loc,
+		Units:    units.system(),
+	}
+	for _, p := range pops {
+		d := day(p.Time)
+
+		p.Weather = d.Weather
+		if h := p.Time.Hour(); h < 6 || h >= 18 {
+			p.Weather = p.Weather.night()
+		}
+		p.Summary = d.Summary
+		p.Temperature = math.NaN()
+		p.ApparentTemperature = math.NaN()
+		p.PrecipAccumulation = math.NaN()
+
+		report.Hourly = append(report.Hourly, p)
+	}
+	for _, d := range daily {
+		report.Daily = append(report.Daily, *d)
+	}
+
+	return report
+}
+
+type jma struct {
+	url        *url.URL
+	httpClient *http.Client
+}
+
+// NewJMA : Create Forecast instance for JMA (気象庁) forecast JSON
+//
+// area は府県予報区のコード (例: 東京都は 130000)
+func NewJMA(area string) Forecast {
+	u, err := url.Parse(jmaAPIBase)
+	if err != nil {
+		return nil
+	}
+
+	u.Path = path.Join(u.Path, "bosai", "forecast", "data", "forecast", area+".json")
+	return &jma{
+		url:        u,
+		httpClient: &http.Client{},
+	}
+}
+
+// Get : Forecast.Get の実装
+//
+// 気象庁の予報は日本語のみのため lang は無視する
+func (f *jma) Get(lang Lang, units Units, _ ...GetOption) (*Report, error) {
+	res, err := f.httpClient.Get(f.url.String())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, jmaError{
+			Code:    res.StatusCode,
+			Message: http.StatusText(res.StatusCode),
+		}
+	}
+
+	r := jmaResponse{}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+
+	return r.report(units), nil
+}
@@ -0,0 +1,281 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"testing"
+	"time"
+)
+
+func TestJMAError_Error(t *testing.T) {
+	err := jmaError{
+		Code:    404,
+		Message: "Not Found",
+	}
+
+	expected := "404: Not Found"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestJMAWeather(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected Weather
+	}{
+		// TEST0 {{{
+		{
+			code:     "100",
+			expected: WeatherClearDay,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			code:     "101",
+			expected: WeatherPartlyCloudyDay,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			code:     "212",
+			expected: WeatherCloudy,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			code:     "209",
+			expected: WeatherFog,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			code:     "306",
+			expected: WeatherRain,
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			code:     "340",
+			expected: WeatherSleet,
+		},
+		// }}}
+		// TEST6 {{{
+		{
+			code:     "405",
+			expected: WeatherSnow,
+		},
+		// }}}
+		// TEST7 {{{
+		{
+			code:     "",
+			expected: WeatherUnknown,
+		},
+		// }}}
+		// TEST8 {{{
+		{
+			code:     "550",
+			expected: WeatherUnknown,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := jmaWeather(tt.code)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewJMA(t *testing.T) {
+	area := "130000"
+
+	fore := NewJMA(area)
+
+	if fore == nil {
+		t.Fatal("function returns nil")
+	}
+
+	f, ok := fore.(*jma)
+	if !ok {
+		t.Fatal("Expected jma instance, but not.")
+	}
+
+	expectedURL := fmt.Sprintf("%s/bosai/forecast/data/forecast/%s.json", jmaAPIBase, area)
+	if f.url == nil {
+		t.Fatal("url is nil")
+	} else if f.url.String() != expectedURL {
+		t.Fatalf("Expected url is %s, but it's %s.", expectedURL, f.url.String())
+	}
+
+	if f.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+}
+
+func jmaFunc(resStatus int, response string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.URL.Path != "/130000.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(resStatus)
+		w.Write([]byte(response))
+	}
+}
+
+func TestJMA_Get(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+	day := func(d int) time.Time {
+		return time.Date(2018, 1, 31+d, 0, 0, 0, 0, tokyo)
+	}
+	daily := func(d int, w Weather, summary string, pop, low, high float64) DailyPoint {
+		return DailyPoint{
+			Time:                    day(d),
+			Weather:                 w,
+			Summary:                 summary,
+			TemperatureHigh:         high,
+			TemperatureLow:          low,
+			ApparentTemperatureHigh: math.NaN(),
+			ApparentTemperatureLow:  math.NaN(),
+			PrecipProbability:       pop,
+			PrecipAccumulation:      math.NaN(),
+		}
+	}
+	hourly := func(d, h int, w Weather, summary string, pop float64) HourlyPoint {
+		return HourlyPoint{
+			Time:                day(d).Add(time.Duration(h) * time.Hour),
+			Weather:             w,
+			Summary:             summary,
+			Temperature:         math.NaN(),
+			ApparentTemperature: math.NaN(),
+			PrecipProbability:   pop,
+			PrecipAccumulation:  math.NaN(),
+		}
+	}
+
+	tests := []struct {
+		path  string
+		units Units
+
+		resStatus  int
+		resMessage string
+
+		expected      *Report
+		expectedError error
+	}{
+		// TEST0 {{{
+		{
+			path:  "/130000.json",
+			units: UnitsSI,
+
+			resStatus:  http.StatusOK,
+			resMessage: readFile("testdata/jma/get00.json"),
+
+			expected: &Report{
+				Location: tokyo,
+				Units:    UnitsSI,
+				Hourly: []HourlyPoint{
+					hourly(0, 12, WeatherClearDay, "晴れ", 0),
+					hourly(0, 18, WeatherClearNight, "晴れ", 0),
+					hourly(1, 0, WeatherPartlyCloudyNight, "くもり時々晴れ", 0.1),
+					hourly(1, 6, WeatherPartlyCloudyDay, "くもり時々晴れ", 0.2),
+					hourly(1, 12, WeatherPartlyCloudyDay, "くもり時々晴れ", 0.2),
+					hourly(1, 18, WeatherPartlyCloudyNight, "くもり時々晴れ", 0.3),
+				},
+				Daily: []DailyPoint{
+					daily(0, WeatherClearDay, "晴れ", 0, math.NaN(), 8),
+					daily(1, WeatherPartlyCloudyDay, "くもり時々晴れ", 0.3, 1, 9),
+					daily(2, WeatherSnow, "雪時々止む", 0.7, -1, 4),
+					daily(3, WeatherCloudy, "", 0.3, 0, 7),
+					daily(4, WeatherPartlyCloudyDay, "", 0.2, 2, 10),
+					daily(5, WeatherRain, "", 0.5, 3, 8),
+					daily(6, WeatherClearDay, "", 0.1, 1, 11),
+				},
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			path:  "/130000.json",
+			units: UnitsUS,
+
+			resStatus:  http.StatusOK,
+			resMessage: `[{"timeSeries":[{"timeDefines":["2018-02-01T00:00:00+09:00","2018-02-01T09:00:00+09:00"],"areas":[{"area":{"name":"東京","code":"44132"},"temps":["-5","10"]}]}]}]`,
+
+			expected: &Report{
+				Location: tokyo,
+				Units:    UnitsUS,
+				Daily: []DailyPoint{
+					daily(1, WeatherUnknown, "", math.NaN(), 23, 50),
+				},
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			path:  "/999999.json",
+			units: UnitsSI,
+
+			expectedError: jmaError{
+				Code:    http.StatusNotFound,
+				Message: "Not Found",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(jmaFunc(tt.resStatus, tt.resMessage)))
+			defer server.Close()
+
+			var err error
+			f := &jma{}
+			f.url, err = neturl.Parse(server.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.httpClient = server.Client()
+
+			res, err := f.Get(LangJa, tt.units)
+			if err != nil {
+				if tt.expectedError == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err.Error() != tt.expectedError.Error() {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expectedError, err)
+				}
+				return
+			}
+
+			if tt.expectedError != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+				return
+			}
+
+			if !sameValue(res, tt.expected) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected, res)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ func (e notifyError) Error() string {
 
 // LineNotify : Line Notify API client interface
 type LineNotify interface {
+	Notifier
 	Send(string) error
 }
 
@@ -45,6 +46,11 @@ func NewLineNotify(token string) LineNotify {
 	}
 }
 
+// Notify : Notifier.Notify の実装
+func (n *lineNotify) Notify(msg *Message) error {
+	return n.Send(msg.Text)
+}
+
 // Send : NotifyClient.Send の実装
 func (n *lineNotify) Send(msg string) error {
 	values := url.Values{}
@@ -167,3 +167,18 @@ func TestLineNotify_Send(t *testing.T) {
 		})
 	}
 }
+
+func TestLineNotify_Notify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(lineNotifyFunc("XXXXX", "TEST", http.StatusOK, "")))
+	defer server.Close()
+
+	n := lineNotify{
+		token:      "XXXXX",
+		url:        server.URL,
+		httpClient: server.Client(),
+	}
+
+	if err := n.Notify(&Message{Text: "TEST"}); err != nil {
+		t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+	}
+}
@@ -0,0 +1,292 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// altText の最大文字数
+	lineAltTextMax = 400
+
+	// カルーセルに含められるバブルの最大数
+	lineCarouselMax = 12
+
+	flexColorHigh    = "#E74C3C"
+	flexColorLow     = "#3498DB"
+	flexColorSub     = "#999999"
+	flexColorBarBase = "#EEEEEE"
+)
+
+type flexBox struct {
+	Type            string        `json:"type"`
+	Layout          string        `json:"layout"`
+	Contents        []interface{} `json:"contents"`
+	Flex            int           `json:"flex,omitempty"`
+	Spacing         string        `json:"spacing,omitempty"`
+	Margin          string        `json:"margin,omitempty"`
+	Width           string        `json:"width,omitempty"`
+	Height          string        `json:"height,omitempty"`
+	BackgroundColor string        `json:"backgroundColor,omitempty"`
+	CornerRadius    string        `json:"cornerRadius,omitempty"`
+	JustifyContent  string        `json:"justifyContent,omitempty"`
+	AlignItems      string        `json:"alignItems,omitempty"`
+}
+
+type flexText struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Flex   int    `json:"flex,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+	Color  string `json:"color,omitempty"`
+	Align  string `json:"align,omitempty"`
+	Wrap   bool   `json:"wrap,omitempty"`
+}
+
+type flexBubble struct {
+	Type   string   `json:"type"`
+	Size   string   `json:"size,omitempty"`
+	Header *flexBox `json:"header,omitempty"`
+	Body   *flexBox `json:"body,omitempty"`
+}
+
+type flexCarousel struct {
+	Type     string       `json:"type"`
+	Contents []flexBubble `json:"contents"`
+}
+
+type lineFlexMessage struct {
+	Type     string       `json:"type"`
+	AltText  string       `json:"altText"`
+	Contents flexCarousel `json:"contents"`
+}
+
+// newFlexMessage : 天気予報を Flex Message (カルーセル) にする
+//
+// 気象警報を1つ目のバブルに、対象日の時間別予報を次のバブルに、日別予報を1日1つのバブルにする。
+// 通知できる予報がなければ nil を返す。
+func newFlexMessage(msg *Message) *lineFlexMessage {
+	f := msg.Format()
+	bubbles := []flexBubble{}
+	if len(msg.Alerts) > 0 {
+		bubbles = append(bubbles, alertBubble(msg.Alerts))
+	}
+	if hourly := msg.HourlyPoints(); len(hourly) > 0 {
+		bubbles = append(bubbles, hourlyBubble(f, msg.Date, hourly))
+	}
+	for _, p := range msg.DailyPoints() {
+		if len(bubbles) >= lineCarouselMax {
+			break
+		}
+		bubbles = append(bubbles, dailyBubble(f, p))
+	}
+
+	if len(bubbles) == 0 {
+		return nil
+	}
+
+	return &lineFlexMessage{
+		Type:    "flex",
+		AltText: altText(msg.Text),
+		Contents: flexCarousel{
+			Type:     "carousel",
+			Contents: bubbles,
+		},
+	}
+}
+
+// altText : テキスト形式の予報を altText の最大文字数までに切り詰める
+func altText(text string) string {
+	r := []rune(strings.TrimSpace(text))
+	if len(r) > lineAltTextMax {
+		r = append(r[:lineAltTextMax-1], '…')
+	}
+
+	return string(r)
+}
+
+// precipColor : 降水確率が高いほど濃い色にする
+func precipColor(p float64) string {
+	switch {
+	case p >= 0.7:
+		return "#08519C"
+	case p >= 0.4:
+		return "#3182BD"
+	default:
+		return "#9ECAE1"
+	}
+}
+
+// precipBar : 降水確率を棒グラフにする
+func precipBar(p float64, flex int) flexBox {
+	width := 0.0
+	if !math.IsNaN(p) {
+		width = math.Max(0, math.Min(1, p)) * 100
+	}
+
+	return flexBox{
+		Type:   "box",
+		Layout: "vertical",
+		Contents: []interface{}{
+			flexBox{
+				Type:            "box",
+				Layout:          "vertical",
+				Contents:        []interface{}{},
+				Width:           fmt.Sprintf("%.0f%%", width),
+				Height:          "8px",
+				BackgroundColor: precipColor(p),
+				CornerRadius:    "4px",
+			},
+		},
+		Flex:            flex,
+		Height:          "8px",
+		BackgroundColor: flexColorBarBase,
+		CornerRadius:    "4px",
+	}
+}
+
+func flexHeader(date time.Time) *flexBox {
+	return &flexBox{
+		Type:   "box",
+		Layout: "vertical",
+		Contents: []interface{}{
+			flexText{
+				Type:   "text",
+				Text:   date.Format("01/02 (Mon)"),
+				Size:   "lg",
+				Weight: "bold",
+			},
+		},
+	}
+}
+
+func alertBubble(alerts []Alert) flexBubble {
+	contents := []interface{}{}
+	for _, a := range alerts {
+		contents = append(contents, flexText{
+			Type:   "text",
+			Text:   AlertText(a),
+			Size:   "sm",
+			Weight: "bold",
+			Color:  flexColorHigh,
+			Wrap:   true,
+		})
+	}
+
+	return flexBubble{
+		Type: "bubble",
+		Size: "mega",
+		Header: &flexBox{
+			Type:   "box",
+			Layout: "vertical",
+			Contents: []interface{}{
+				flexText{
+					Type:   "text",
+					Text:   "Weather alert",
+					Size:   "lg",
+					Weight: "bold",
+					Color:  flexColorHigh,
+				},
+			},
+		},
+		Body: &flexBox{
+			Type:     "box",
+			Layout:   "vertical",
+			Contents: contents,
+			Spacing:  "sm",
+		},
+	}
+}
+
+func hourlyBubble(f Formatter, date time.Time, points []HourlyPoint) flexBubble {
+	rows := []interface{}{}
+	for _, p := range points {
+		rows = append(rows, flexBox{
+			Type:   "box",
+			Layout: "horizontal",
+			Contents: []interface{}{
+				flexText{Type: "text", Text: p.Time.Format("15:04"), Flex: 2, Size: "sm"},
+				flexText{Type: "text", Text: IconText(p.Weather), Flex: 1, Size: "sm", Align: "center"},
+				flexText{Type: "text", Text: f.Temperature(p.Temperature), Flex: 2, Size: "sm", Align: "end"},
+				flexText{Type: "text", Text: f.Temperature(p.ApparentTemperature), Flex: 2, Size: "xs", Align: "end", Color: flexColorSub},
+				precipBar(p.PrecipProbability, 2),
+				flexText{Type: "text", Text: f.Precip(p.Weather, p.PrecipProbability, p.PrecipAccumulation), Flex: 2, Size: "xs", Align: "end"},
+			},
+			Spacing:    "sm",
+			AlignItems: "center",
+		})
+	}
+
+	return flexBubble{
+		Type:   "bubble",
+		Size:   "mega",
+		Header: flexHeader(date),
+		Body: &flexBox{
+			Type:     "box",
+			Layout:   "vertical",
+			Contents: rows,
+			Spacing:  "sm",
+		},
+	}
+}
+
+func temperatureRow(f Formatter, mark, color string, t, apparent float64, at time.Time) flexBox {
+	return flexBox{
+		Type:   "box",
+		Layout: "horizontal",
+		Contents: []interface{}{
+			flexText{Type: "text", Text: mark, Flex: 1, Color: color},
+			flexText{Type: "text", Text: f.Temperature(t), Flex: 3, Weight: "bold", Color: color},
+			flexText{Type: "text", Text: f.Temperature(apparent), Flex: 3, Size: "sm", Color: flexColorSub},
+			flexText{Type: "text", Text: FormatTime("15:04", at), Flex: 2, Size: "sm", Align: "end", Color: flexColorSub},
+		},
+		AlignItems: "center",
+	}
+}
+
+func dailyBubble(f Formatter, p DailyPoint) flexBubble {
+	contents := []interface{}{
+		flexText{
+			Type: "text",
+			Text: IconText(p.Weather),
+			Size: "3xl",
+		},
+	}
+	if p.Summary != "" {
+		contents = append(contents, flexText{
+			Type: "text",
+			Text: p.Summary,
+			Size: "sm",
+			Wrap: true,
+		})
+	}
+	contents = append(contents,
+		temperatureRow(f, "▲", flexColorHigh, p.TemperatureHigh, p.ApparentTemperatureHigh, p.ApparentTemperatureHighTime),
+		temperatureRow(f, "▼", flexColorLow, p.TemperatureLow, p.ApparentTemperatureLow, p.ApparentTemperatureLowTime),
+		flexBox{
+			Type:   "box",
+			Layout: "horizontal",
+			Contents: []interface{}{
+				precipBar(p.PrecipProbability, 3),
+				flexText{Type: "text", Text: f.Precip(p.Weather, p.PrecipProbability, p.PrecipAccumulation), Flex: 1, Size: "sm", Align: "end"},
+			},
+			Spacing:    "sm",
+			AlignItems: "center",
+		},
+	)
+
+	return flexBubble{
+		Type:   "bubble",
+		Size:   "kilo",
+		Header: flexHeader(p.Time),
+		Body: &flexBox{
+			Type:     "box",
+			Layout:   "vertical",
+			Contents: contents,
+			Spacing:  "md",
+		},
+	}
+}
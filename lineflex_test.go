@@ -0,0 +1,104 @@
+package weatherline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAltText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			text:     "\n01/31\n  09:00 ☀ 2.6℃/-0.6℃ 3%\n",
+			expected: "01/31\n  09:00 ☀ 2.6℃/-0.6℃ 3%",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			text:     strings.Repeat("あ", 401),
+			expected: strings.Repeat("あ", 399) + "…",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := altText(tt.text)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewFlexMessage(t *testing.T) {
+	report := unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report()
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		msg *Message
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: report,
+			},
+
+			expected: readFile("testdata/line/flex00.json"),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 3, 1, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   "\n03/01\n",
+				Report: report,
+			},
+
+			expected: "null\n",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			msg: alertMessage(tokyo),
+
+			expected: readFile("testdata/line/flex01.json"),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(newFlexMessage(tt.msg)); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package weatherline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	lineMessagingAPIBase = "https://api.line.me"
+)
+
+type lineMessagingError struct {
+	Status  int    `json:"-"`
+	Message string `json:"message"`
+	Details []struct {
+		Message  string `json:"message"`
+		Property string `json:"property"`
+	} `json:"details"`
+}
+
+func (e lineMessagingError) Error() string {
+	msg := e.Message
+	if len(e.Details) > 0 {
+		details := []string{}
+		for _, d := range e.Details {
+			details = append(details, fmt.Sprintf("%s: %s", d.Property, d.Message))
+		}
+		msg = fmt.Sprintf("%s (%s)", msg, strings.Join(details, ", "))
+	}
+
+	return fmt.Sprintf("%d: %s", e.Status, msg)
+}
+
+type lineMessaging struct {
+	token string
+	to    []string
+
+	url        string
+	httpClient *http.Client
+}
+
+// NewLineMessaging : Create Notifier instance for LINE Messaging API (push message)
+//
+// to にはユーザー ID、グループ ID またはトークルーム ID を指定する。
+func NewLineMessaging(token string, to []string) Notifier {
+	return &lineMessaging{
+		token: token,
+		to:    to,
+
+		url:        lineMessagingAPIBase,
+		httpClient: &http.Client{},
+	}
+}
+
+type lineTextMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type linePushRequest struct {
+	To       string        `json:"to"`
+	Messages []interface{} `json:"messages"`
+}
+
+// messages : 送信するメッセージオブジェクト
+//
+// 予報があれば Flex Message にし、なければテキストを送る
+func (n *lineMessaging) messages(msg *Message) []interface{} {
+	if flex := newFlexMessage(msg); flex != nil {
+		return []interface{}{flex}
+	}
+
+	return []interface{}{
+		lineTextMessage{
+			Type: "text",
+			Text: strings.TrimSpace(msg.Text),
+		},
+	}
+}
+
+// payload : 宛先ごとのリクエストボディ
+func (n *lineMessaging) payload(to string, msg *Message) ([]byte, error) {
+	return json.Marshal(linePushRequest{
+		To:       to,
+		Messages: n.messages(msg),
+	})
+}
+
+// Notify : Notifier.Notify の実装
+//
+// 宛先ごとに送信し、失敗した宛先があっても残りの宛先には送信する。
+// エラーは最初に失敗したものを返す。
+func (n *lineMessaging) Notify(msg *Message) error {
+	var first error
+	for _, to := range n.to {
+		if err := n.push(to, msg); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+func (n *lineMessaging) push(to string, msg *Message) error {
+	body, err := n.payload(to, msg)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(n.url)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "v2", "bot", "message", "push")
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", n.token))
+
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	response := lineMessagingError{}
+	if err := json.Unmarshal(b, &response); err != nil {
+		response.Message = strings.TrimSpace(string(b))
+	}
+	response.Status = res.StatusCode
+
+	return response
+}
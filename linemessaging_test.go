@@ -0,0 +1,286 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLineMessagingError_Error(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			body:     `{"message":"Authentication failed due to the following reason: invalid token. Confirm that the access token in the authorization header is valid."}`,
+			expected: "401: Authentication failed due to the following reason: invalid token. Confirm that the access token in the authorization header is valid.",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			body:     `{"message":"The request body has 1 error(s)","details":[{"message":"The property, 'to', in the request body is invalid","property":"to"}]}`,
+			expected: "401: The request body has 1 error(s) (to: The property, 'to', in the request body is invalid)",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			err := lineMessagingError{}
+			if e := json.Unmarshal([]byte(tt.body), &err); e != nil {
+				t.Fatal(e)
+			}
+			err.Status = 401
+
+			actual := err.Error()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewLineMessaging(t *testing.T) {
+	token := "abcde"
+	to := []string{"U0123", "C4567"}
+
+	notifier := NewLineMessaging(token, to)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*lineMessaging)
+	if !ok {
+		t.Fatal("Expected lineMessaging instance, but not.")
+	}
+
+	if n.token != token {
+		t.Fatalf("token is not same: %s != %s", n.token, token)
+	}
+
+	if !reflect.DeepEqual(n.to, to) {
+		t.Fatalf("to is not same: %v != %v", n.to, to)
+	}
+
+	if n.url != lineMessagingAPIBase {
+		t.Fatalf("Expected url is %s, but it's %s.", lineMessagingAPIBase, n.url)
+	}
+
+	if n.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+}
+
+func writeLineMessagingResponse(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	if _, err := fmt.Fprintf(w, `{"message":"%s"}`, message); err != nil {
+		panic(err)
+	}
+}
+
+func lineMessagingFunc(token string, mutex *sync.Mutex, received *[]linePushRequest, failTo string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeLineMessagingResponse(w, http.StatusMethodNotAllowed, fmt.Sprintf("Unexpected request: method = %s", r.Method))
+			return
+		}
+
+		if r.URL.Path != "/v2/bot/message/push" {
+			writeLineMessagingResponse(w, http.StatusNotFound, fmt.Sprintf("Unexpected request: path = %s", r.URL.Path))
+			return
+		}
+
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			writeLineMessagingResponse(w, http.StatusBadRequest, fmt.Sprintf("Unexpected request: `Content-Type` header = %s", contentType))
+			return
+		}
+
+		if authorization := r.Header.Get("Authorization"); authorization != "Bearer "+token {
+			writeLineMessagingResponse(w, http.StatusUnauthorized, "Authentication failed")
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeLineMessagingResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		req := linePushRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeLineMessagingResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		mutex.Lock()
+		*received = append(*received, req)
+		mutex.Unlock()
+
+		if req.To == failTo {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"The request body has 1 error(s)","details":[{"message":"Invalid to","property":"to"}]}`)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{}`)
+	}
+}
+
+func TestLineMessaging_Notify(t *testing.T) {
+	tests := []struct {
+		token  string
+		failTo string
+
+		n   lineMessaging
+		msg *Message
+
+		expectedTo   []string
+		expectedText string
+		expected     error
+	}{
+		// TEST0 {{{
+		{
+			token: "XXXXX",
+
+			n:   lineMessaging{token: "XXXXX", to: []string{"U0123", "C4567"}},
+			msg: &Message{Text: "\n01/31\n  09:00 晴れ\n"},
+
+			expectedTo:   []string{"U0123", "C4567"},
+			expectedText: "01/31\n  09:00 晴れ",
+			expected:     nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			token: "XXXXX",
+
+			n:   lineMessaging{token: "YYYYY", to: []string{"U0123"}},
+			msg: &Message{Text: "TEST1"},
+
+			expected: fmt.Errorf("%d: Authentication failed", http.StatusUnauthorized),
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			token:  "XXXXX",
+			failTo: "U0123",
+
+			n:   lineMessaging{token: "XXXXX", to: []string{"U0123", "C4567"}},
+			msg: &Message{Text: "TEST2"},
+
+			expectedTo:   []string{"U0123", "C4567"},
+			expectedText: "TEST2",
+			expected:     fmt.Errorf("%d: The request body has 1 error(s) (to: Invalid to)", http.StatusBadRequest),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			var mutex sync.Mutex
+			received := []linePushRequest{}
+			server := httptest.NewTLSServer(http.HandlerFunc(lineMessagingFunc(tt.token, &mutex, &received, tt.failTo)))
+			defer server.Close()
+
+			tt.n.httpClient = server.Client()
+			tt.n.url = server.URL
+
+			err := tt.n.Notify(tt.msg)
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err.Error() != tt.expected.Error() {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+
+			to := []string{}
+			for _, req := range received {
+				to = append(to, req.To)
+
+				expected := map[string]interface{}{"type": "text", "text": tt.expectedText}
+				if len(req.Messages) != 1 || !reflect.DeepEqual(req.Messages[0], expected) {
+					t.Errorf("Expected to get [%v], but got [%v]", expected, req.Messages)
+				}
+			}
+			if len(tt.expectedTo) > 0 && !reflect.DeepEqual(to, tt.expectedTo) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expectedTo, to)
+			}
+		})
+	}
+}
+
+func TestLineMessaging_messages(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		msg *Message
+
+		expectedType string
+	}{
+		// TEST0 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+			},
+
+			expectedType: "flex",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: &Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Text: "TEST1",
+			},
+
+			expectedType: "text",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			n := &lineMessaging{}
+			b, err := n.payload("U0123", tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := struct {
+				Messages []struct {
+					Type string `json:"type"`
+				} `json:"messages"`
+			}{}
+			if err := json.Unmarshal(b, &req); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(req.Messages) != 1 || req.Messages[0].Type != tt.expectedType {
+				t.Errorf("Expected to get a %s message, but got [%s]", tt.expectedType, string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package weatherline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// 再送する最大回数
+	matrixRetryMax = 3
+
+	// 5xx や通信エラーのときに再送するまでの時間
+	matrixRetryInterval = 5 * time.Second
+)
+
+var matrixTxnCount uint64
+
+type matrixError struct {
+	Status       int    `json:"-"`
+	Code         string `json:"errcode"`
+	Message      string `json:"error"`
+	RetryAfterMs int64  `json:"retry_after_ms"`
+}
+
+func (e matrixError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Status, e.Message)
+}
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+type matrix struct {
+	token  string
+	roomID string
+
+	url        string
+	httpClient *http.Client
+	sleep      func(time.Duration)
+	txnID      func() string
+}
+
+// NewMatrix : Create Notifier instance for Matrix (Client-Server API)
+//
+// roomID にはルーム ID (!xxxx:example.org) を指定する。
+func NewMatrix(homeserverURL, token, roomID string) Notifier {
+	return &matrix{
+		token:  token,
+		roomID: roomID,
+
+		url:        homeserverURL,
+		httpClient: &http.Client{},
+		sleep:      time.Sleep,
+		txnID:      newMatrixTxnID,
+	}
+}
+
+// newMatrixTxnID : 送信ごとに一意なトランザクション ID
+func newMatrixTxnID() string {
+	return fmt.Sprintf("weatherline.%d.%d", time.Now().UnixNano(), atomic.AddUint64(&matrixTxnCount, 1))
+}
+
+// payload : リクエストボディ
+//
+// 予報があれば HTML 形式の予報を formatted_body にする
+func (n *matrix) payload(msg *Message) ([]byte, error) {
+	html, err := forecastHTML(forecastTemplate, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := matrixMessage{
+		MsgType: "m.text",
+		Body:    strings.TrimSpace(msg.Text),
+	}
+	if html != "" {
+		m.Format = "org.matrix.custom.html"
+		m.FormattedBody = html
+	}
+
+	return json.Marshal(m)
+}
+
+// Notify : Notifier.Notify の実装
+//
+// 429、5xx、通信エラーのときは同じトランザクション ID で再送する。
+// ホームサーバーは同じトランザクション ID のイベントを重複して送信しない。
+func (n *matrix) Notify(msg *Message) error {
+	body, err := n.payload(msg)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(n.url)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "_matrix", "client", "v3", "rooms", n.roomID, "send", "m.room.message", n.txnID())
+
+	for i := 0; ; i++ {
+		retryAfter, err := n.put(u.String(), body)
+		if err == nil {
+			return nil
+		}
+		if retryAfter < 0 || i >= matrixRetryMax {
+			return err
+		}
+
+		n.sleep(retryAfter)
+	}
+}
+
+// put : 再送できる場合は再送までの時間を返す (再送できない場合は負の値)
+func (n *matrix) put(u string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", n.token))
+
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return matrixRetryInterval, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return 0, nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return matrixRetryInterval, err
+	}
+
+	response := matrixError{}
+	if err := json.Unmarshal(b, &response); err != nil || response.Message == "" {
+		response.Message = strings.TrimSpace(string(b))
+	}
+	response.Status = res.StatusCode
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		return time.Duration(response.RetryAfterMs) * time.Millisecond, response
+	case res.StatusCode >= http.StatusInternalServerError:
+		return matrixRetryInterval, response
+	default:
+		return -1, response
+	}
+}
@@ -0,0 +1,349 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMatrixError_Error(t *testing.T) {
+	err := matrixError{
+		Status:  403,
+		Code:    "M_FORBIDDEN",
+		Message: "User @weatherline:example.org not in room !room:example.org",
+	}
+
+	expected := "403: User @weatherline:example.org not in room !room:example.org"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestNewMatrix(t *testing.T) {
+	homeserverURL := "https://matrix.example.org"
+	token := "syt_XXXX"
+	roomID := "!room:example.org"
+
+	notifier := NewMatrix(homeserverURL, token, roomID)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*matrix)
+	if !ok {
+		t.Fatal("Expected matrix instance, but not.")
+	}
+
+	if n.url != homeserverURL {
+		t.Fatalf("Expected url is %s, but it's %s.", homeserverURL, n.url)
+	}
+
+	if n.token != token {
+		t.Fatalf("Expected token is %s, but it's %s.", token, n.token)
+	}
+
+	if n.roomID != roomID {
+		t.Fatalf("Expected roomID is %s, but it's %s.", roomID, n.roomID)
+	}
+
+	if n.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+
+	if n.sleep == nil {
+		t.Fatal("sleep is nil")
+	}
+
+	if n.txnID == nil {
+		t.Fatal("txnID is nil")
+	}
+}
+
+func TestNewMatrixTxnID(t *testing.T) {
+	a := newMatrixTxnID()
+	b := newMatrixTxnID()
+
+	if !strings.HasPrefix(a, "weatherline.") {
+		t.Errorf("Expected to start with [weatherline.], but got [%s]", a)
+	}
+
+	if a == b {
+		t.Errorf("Expected to get unique IDs, but got [%s] twice", a)
+	}
+}
+
+func TestMatrix_payload(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		msg *Message
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+			},
+
+			expected: readFile("testdata/matrix/payload00.json"),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: &Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Text: "\n01/31\n",
+			},
+
+			expected: "{\n  \"msgtype\": \"m.text\",\n  \"body\": \"01/31\"\n}\n",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			n := &matrix{}
+			b, err := n.payload(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := indentJSON(b)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+type matrixResponse struct {
+	status int
+	body   string
+}
+
+// fakeHomeserver : responses を順に返し、トランザクション ID ごとにイベントを記録する
+type fakeHomeserver struct {
+	token     string
+	roomID    string
+	responses []matrixResponse
+
+	mu     sync.Mutex
+	count  int
+	txnIDs []string
+	events map[string]matrixMessage
+}
+
+func (s *fakeHomeserver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix := fmt.Sprintf("/_matrix/client/v3/rooms/%s/send/m.room.message/", s.roomID)
+	if r.Method != http.MethodPut || !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errcode":"M_UNRECOGNIZED","error":"Unrecognized request"}`)
+		return
+	}
+
+	if r.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", s.token) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"errcode":"M_UNKNOWN_TOKEN","error":"Invalid access token passed."}`)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	m := matrixMessage{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errcode":"M_NOT_JSON","error":"Content not JSON."}`)
+		return
+	}
+
+	txnID := strings.TrimPrefix(r.URL.Path, prefix)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.txnIDs = append(s.txnIDs, txnID)
+
+	res := s.responses[s.count]
+	if s.count < len(s.responses)-1 {
+		s.count++
+	}
+
+	if res.status == http.StatusOK {
+		// 同じトランザクション ID のイベントは1つだけ
+		s.events[txnID] = m
+	}
+
+	w.WriteHeader(res.status)
+	fmt.Fprint(w, res.body)
+}
+
+func TestMatrix_Notify(t *testing.T) {
+	tests := []struct {
+		token     string
+		responses []matrixResponse
+
+		expectedTxnIDs []string
+		expectedSleeps []time.Duration
+		expectedEvents int
+		expected       error
+	}{
+		// TEST0 {{{
+		{
+			token: "syt_XXXX",
+			responses: []matrixResponse{
+				{status: http.StatusOK, body: `{"event_id":"$event"}`},
+			},
+
+			expectedTxnIDs: []string{"txn1"},
+			expectedSleeps: []time.Duration{},
+			expectedEvents: 1,
+			expected:       nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			token: "syt_YYYY",
+			responses: []matrixResponse{
+				{status: http.StatusOK, body: `{"event_id":"$event"}`},
+			},
+
+			expectedTxnIDs: nil,
+			expectedSleeps: []time.Duration{},
+			expectedEvents: 0,
+			expected: matrixError{
+				Status:  http.StatusUnauthorized,
+				Code:    "M_UNKNOWN_TOKEN",
+				Message: "Invalid access token passed.",
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			token: "syt_XXXX",
+			responses: []matrixResponse{
+				{status: http.StatusTooManyRequests, body: `{"errcode":"M_LIMIT_EXCEEDED","error":"Too Many Requests","retry_after_ms":2000}`},
+				{status: http.StatusBadGateway, body: "Bad Gateway\n"},
+				{status: http.StatusOK, body: `{"event_id":"$event"}`},
+			},
+
+			expectedTxnIDs: []string{"txn1", "txn1", "txn1"},
+			expectedSleeps: []time.Duration{2 * time.Second, matrixRetryInterval},
+			expectedEvents: 1,
+			expected:       nil,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			token: "syt_XXXX",
+			responses: []matrixResponse{
+				{status: http.StatusServiceUnavailable, body: `{"errcode":"M_UNKNOWN","error":"Service Unavailable"}`},
+			},
+
+			expectedTxnIDs: []string{"txn1", "txn1", "txn1", "txn1"},
+			expectedSleeps: []time.Duration{matrixRetryInterval, matrixRetryInterval, matrixRetryInterval},
+			expectedEvents: 0,
+			expected: matrixError{
+				Status:  http.StatusServiceUnavailable,
+				Code:    "M_UNKNOWN",
+				Message: "Service Unavailable",
+			},
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			token: "syt_XXXX",
+			responses: []matrixResponse{
+				{status: http.StatusForbidden, body: `{"errcode":"M_FORBIDDEN","error":"User not in room"}`},
+			},
+
+			expectedTxnIDs: []string{"txn1"},
+			expectedSleeps: []time.Duration{},
+			expectedEvents: 0,
+			expected: matrixError{
+				Status:  http.StatusForbidden,
+				Code:    "M_FORBIDDEN",
+				Message: "User not in room",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			homeserver := &fakeHomeserver{
+				token:     "syt_XXXX",
+				roomID:    "!room:example.org",
+				responses: tt.responses,
+				events:    map[string]matrixMessage{},
+			}
+			server := httptest.NewTLSServer(homeserver)
+			defer server.Close()
+
+			sleeps := []time.Duration{}
+			txn := 0
+			n := &matrix{
+				token:  tt.token,
+				roomID: "!room:example.org",
+
+				url:        server.URL,
+				httpClient: server.Client(),
+				sleep: func(d time.Duration) {
+					sleeps = append(sleeps, d)
+				},
+				txnID: func() string {
+					txn++
+					return fmt.Sprintf("txn%d", txn)
+				},
+			}
+
+			err := n.Notify(&Message{Text: "TEST"})
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err != tt.expected {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+
+			if !reflect.DeepEqual(sleeps, tt.expectedSleeps) {
+				t.Errorf("Expected to sleep %v, but slept %v", tt.expectedSleeps, sleeps)
+			}
+
+			homeserver.mu.Lock()
+			defer homeserver.mu.Unlock()
+
+			if !reflect.DeepEqual(homeserver.txnIDs, tt.expectedTxnIDs) {
+				t.Errorf("Expected transaction IDs are %v, but got %v", tt.expectedTxnIDs, homeserver.txnIDs)
+			}
+
+			if len(homeserver.events) != tt.expectedEvents {
+				t.Errorf("Expected %d events, but got %d", tt.expectedEvents, len(homeserver.events))
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	metNorwayAPIBase = "https://api.met.no"
+
+	defaultUserAgent = "weatherline github.com/yyotti/weatherline"
+
+	// 降水量 (融かした水の量) に対する積雪の深さの比 (一般的な 10:1 とする)
+	metNorwaySnowRatio = 10.0
+)
+
+type metNorwayError struct {
+	Code    int
+	Message string
+}
+
+func (e metNorwayError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+var metNorwaySymbols = map[string]struct {
+	weather   Weather
+	summaries map[Lang]string
+}{
+	"clearsky":     {WeatherClearDay, map[Lang]string{LangEn: "Clear sky", LangJa: "快晴"}},
+	"fair":         {WeatherPartlyCloudyDay, map[Lang]string{LangEn: "Fair", LangJa: "晴れ"}},
+	"partlycloudy": {WeatherPartlyCloudyDay, map[Lang]string{LangEn: "Partly cloudy", LangJa: "晴れ時々曇り"}},
+	"cloudy":       {WeatherCloudy, map[Lang]string{LangEn: "Cloudy", LangJa: "曇り"}},
+	"fog":          {WeatherFog, map[Lang]string{LangEn: "Fog", LangJa: "霧"}},
+}
+
+var metNorwayPrecips = map[string]struct {
+	weather   Weather
+	summaries map[Lang]string
+}{
+	"rain":  {WeatherRain, map[Lang]string{LangEn: "rain", LangJa: "雨"}},
+	"sleet": {WeatherSleet, map[Lang]string{LangEn: "sleet", LangJa: "みぞれ"}},
+	"snow":  {WeatherSnow, map[Lang]string{LangEn: "snow", LangJa: "雪"}},
+}
+
+// metNorwaySymbol : symbol_code (例: partlycloudy_day, lightsnowshowersandthunder) を天気種別と概要に変換する
+func metNorwaySymbol(code string, lang Lang) (Weather, string) {
+	if lang != LangJa {
+		lang = LangEn
+	}
+
+	night := strings.HasSuffix(code, "_night")
+	if i := strings.Index(code, "_"); i >= 0 {
+		code = code[:i]
+	}
+
+	weather, summary := WeatherUnknown, ""
+	if s, ok := metNorwaySymbols[code]; ok {
+		weather, summary = s.weather, s.summaries[lang]
+	} else {
+		// [light|heavy]{rain|sleet|snow}[showers][andthunder]
+		// ("lightssleet...", "lightssnow..." という綴りのものもある)
+		thunder := strings.HasSuffix(code, "andthunder")
+		code = strings.TrimSuffix(code, "andthunder")
+		showers := strings.HasSuffix(code, "showers")
+		code = strings.TrimSuffix(code, "showers")
+		intensity := ""
+		for _, prefix := range []string{"light", "heavy"} {
+			if strings.HasPrefix(code, prefix) {
+				intensity = prefix
+				code = strings.TrimPrefix(code, prefix)
+				if strings.HasPrefix(code, "ss") {
+					code = code[1:]
+				}
+			}
+		}
+
+		p, ok := metNorwayPrecips[code]
+		if !ok {
+			return WeatherUnknown, ""
+		}
+
+		weather = p.weather
+		switch lang {
+		case LangJa:
+			switch intensity {
+			case "light":
+				summary = "弱い"
+			case "heavy":
+				summary = "強い"
+			}
+			if showers {
+				summary += "にわか"
+			}
+			summary += p.summaries[lang]
+			if thunder {
+				summary += "と雷"
+			}
+		default:
+			words := []string{}
+			if intensity != "" {
+				words = append(words, intensity)
+			}
+			words = append(words, p.summaries[lang])
+			if showers {
+				words = append(words, "showers")
+			}
+			if thunder {
+				words = append(words, "and thunder")
+			}
+			summary = strings.Join(words, " ")
+			summary = strings.ToUpper(summary[:1]) + summary[1:]
+		}
+	}
+
+	if night {
+		weather = weather.night()
+	}
+
+	return weather, summary
+}
+
+type metNorwaySummary struct {
+	SymbolCode string `json:"symbol_code"`
+}
+
+type metNorwayPeriod struct {
+	Summary metNorwaySummary `json:"summary"`
+	Details struct {
+		PrecipitationAmount *float64 `json:"precipitation_amount"` // mm
+	} `json:"details"`
+}
+
+type metNorwayResponse struct {
+	Properties struct {
+		TimeSeries []struct {
+			Time time.Time `json:"time"`
+			Data struct {
+				Instant struct {
+					Details struct {
+						AirTemperature   float64 `json:"air_temperature"`   // ℃
+						RelativeHumidity float64 `json:"relative_humidity"` // %
+						WindSpeed        float64 `json:"wind_speed"`        // m/s
+					} `json:"details"`
+				} `json:"instant"`
+				Next1Hours *metNorwayPeriod `json:"next_1_hours"`
+				Next6Hours *metNorwayPeriod `json:"next_6_hours"`
+			} `json:"data"`
+		} `json:"timeseries"`
+	} `json:"properties"`
+}
+
+func (r *metNorwayResponse) report(loc *time.Location, lang Lang, units Units) *Report {
+	temperature := func(c float64) float64 {
+		if units == UnitsUS {
+			return fahrenheit(c)
+		}
+		return c
+	}
+
+	report := &Report{
+		Location: loc,
+		Units:    units.system(),
+	}
+	for _, ts := range r.Properties.TimeSeries {
+		period := ts.Data.Next1Hours
+		if period == nil {
+			period = ts.Data.Next6Hours
+		}
+		if period == nil {
+			// 予報期間の最後は瞬間値しかない
+			continue
+		}
+
+		details := ts.Data.Instant.Details
+		weather, summary := metNorwaySymbol(period.Summary.SymbolCode, lang)
+
+		accumulation := 0.0
+		if weather == WeatherSnow && period.Details.PrecipitationAmount != nil {
+			accumulation = snowAccumulation(*period.Details.PrecipitationAmount)
+			if units == UnitsUS {
+				accumulation = inches(accumulation)
+			}
+		}
+
+		report.Hourly = append(report.Hourly, HourlyPoint{
+			Time:                ts.Time.In(loc),
+			Weather:             weather,
+			Summary:             summary,
+			Temperature:         temperature(details.AirTemperature),
+			ApparentTemperature: temperature(apparentTemperature(details.AirTemperature, details.RelativeHumidity, details.WindSpeed)),
+			PrecipProbability:   math.NaN(), // compact には含まれない
+			PrecipAccumulation:  accumulation,
+		})
+	}
+	report.Daily = dailyFromHourly(report.Hourly)
+
+	return report
+}
+
+// metNorwayCache : If-Modified-Since と Expires のためのキャッシュ
+type metNorwayCache struct {
+	Expires      time.Time       `json:"expires"`
+	LastModified string          `json:"lastModified"`
+	Body         json.RawMessage `json:"body"`
+}
+
+type metNorway struct {
+	lat       string
+	long      string
+	userAgent string
+	location  *time.Location
+
+	url        *url.URL
+	httpClient *http.Client
+	cache      Cache
+	now        func() time.Time
+}
+
+// NewMETNorway : Create Forecast instance for MET Norway Locationforecast API
+//
+// API はタイムゾーンを返さないので、予報地点のタイムゾーン (IANA 名) を timezone に指定する。
+// タイムゾーンが不正な場合は nil を返す。
+// userAgent は利用規約により必須 (空の場合は既定値を使う)。
+// cache を指定すると Expires までレスポンスを再利用する。
+func NewMETNorway(lat, long, timezone, userAgent string, cache Cache) Forecast {
+	loc, err := loadTimezone(timezone)
+	if err != nil {
+		return nil
+	}
+
+	u, err := url.Parse(metNorwayAPIBase)
+	if err != nil {
+		return nil
+	}
+
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+
+	u.Path = path.Join(u.Path, "weatherapi", "locationforecast", "2.0", "compact")
+	return &metNorway{
+		lat:       lat,
+		long:      long,
+		userAgent: userAgent,
+		location:  loc,
+
+		url:        u,
+		httpClient: &http.Client{},
+		cache:      cache,
+		now:        time.Now,
+	}
+}
+
+// loadTimezone : IANA 名のタイムゾーン
+//
+// 実行するマシンのタイムゾーンになってしまうので、空文字や "Local" は受け付けない。
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("timezone is required")
+	}
+
+	return time.LoadLocation(name)
+}
+
+// ValidateTimezone : NewMETNorway に指定できるタイムゾーンかどうかを検査する
+func ValidateTimezone(name string) error {
+	_, err := loadTimezone(name)
+	return err
+}
+
+// snowAccumulation : 降水量 (mm) から metNorwaySnowRatio で積雪量 (cm) を見積もる
+func snowAccumulation(precipitation float64) float64 {
+	return precipitation * metNorwaySnowRatio / 10
+}
+
+// coordinate : 利用規約により小数点以下4桁までに丸める
+func coordinate(s string) string {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+
+	return strconv.FormatFloat(math.Round(v*10000)/10000, 'f', -1, 64)
+}
+
+// Get : Forecast.Get の実装
+func (f *metNorway) Get(lang Lang, units Units, _ ...GetOption) (*Report, error) {
+	values := url.Values{}
+	values.Set("lat", coordinate(f.lat))
+	values.Set("lon", coordinate(f.long))
+
+	u := *f.url
+	u.RawQuery = values.Encode()
+
+	body, err := f.fetch(u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	r := metNorwayResponse{}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+
+	return r.report(f.location, lang, units), nil
+}
+
+func (f *metNorway) fetch(u string) ([]byte, error) {
+	key := "met-norway:" + u
+
+	var cached *metNorwayCache
+	if f.cache != nil {
+		if b, ok := f.cache.Get(key); ok {
+			c := metNorwayCache{}
+			if err := json.Unmarshal(b, &c); err == nil {
+				cached = &c
+			}
+		}
+	}
+
+	if cached != nil && f.now().Before(cached.Expires) {
+		return cached.Body, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", f.userAgent)
+	if cached != nil && cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+
+	res, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && cached != nil:
+		body = cached.Body
+
+	case res.StatusCode == http.StatusOK, res.StatusCode == http.StatusNonAuthoritativeInfo:
+		cached = &metNorwayCache{
+			LastModified: res.Header.Get("Last-Modified"),
+			Body:         body,
+		}
+
+	default:
+		return nil, metNorwayError{
+			Code:    res.StatusCode,
+			Message: strings.TrimSpace(string(body)),
+		}
+	}
+
+	if f.cache != nil {
+		if expires, err := http.ParseTime(res.Header.Get("Expires")); err == nil {
+			cached.Expires = expires
+		}
+		if b, err := json.Marshal(cached); err == nil {
+			// キャッシュできなくても予報は返す
+			_ = f.cache.Set(key, b)
+		}
+	}
+
+	return body, nil
+}
@@ -0,0 +1,681 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMETNorwayError_Error(t *testing.T) {
+	err := metNorwayError{
+		Code:    403,
+		Message: "Forbidden",
+	}
+
+	expected := "403: Forbidden"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestMETNorwaySymbol(t *testing.T) {
+	tests := []struct {
+		code string
+		lang Lang
+
+		expectedWeather Weather
+		expectedSummary string
+	}{
+		// TEST0 {{{
+		{
+			code: "clearsky_day",
+			lang: LangEn,
+
+			expectedWeather: WeatherClearDay,
+			expectedSummary: "Clear sky",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			code: "partlycloudy_night",
+			lang: LangJa,
+
+			expectedWeather: WeatherPartlyCloudyNight,
+			expectedSummary: "晴れ時々曇り",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			code: "fair_polartwilight",
+			lang: LangEn,
+
+			expectedWeather: WeatherPartlyCloudyDay,
+			expectedSummary: "Fair",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			code: "lightsnow",
+			lang: LangEn,
+
+			expectedWeather: WeatherSnow,
+			expectedSummary: "Light snow",
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			code: "lightssleetshowersandthunder_day",
+			lang: LangJa,
+
+			expectedWeather: WeatherSleet,
+			expectedSummary: "弱いにわかみぞれと雷",
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			code: "heavyrainshowers_night",
+			lang: LangUnknown,
+
+			expectedWeather: WeatherRain,
+			expectedSummary: "Heavy rain showers",
+		},
+		// }}}
+		// TEST6 {{{
+		{
+			code: "rainandthunder",
+			lang: LangEn,
+
+			expectedWeather: WeatherRain,
+			expectedSummary: "Rain and thunder",
+		},
+		// }}}
+		// TEST7 {{{
+		{
+			code: "unknown",
+			lang: LangEn,
+
+			expectedWeather: WeatherUnknown,
+			expectedSummary: "",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			weather, summary := metNorwaySymbol(tt.code, tt.lang)
+			if weather != tt.expectedWeather {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expectedWeather, weather)
+			}
+			if summary != tt.expectedSummary {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expectedSummary, summary)
+			}
+		})
+	}
+}
+
+func TestSnowAccumulation(t *testing.T) {
+	tests := []struct {
+		precipitation float64
+
+		expected float64
+	}{
+		// TEST0 {{{
+		{
+			precipitation: 0,
+
+			expected: 0,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			precipitation: 1,
+
+			expected: 1,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			precipitation: 2.5,
+
+			expected: 2.5,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := snowAccumulation(tt.precipitation)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCoordinate(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			s:        "34.920812345",
+			expected: "34.9208",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			s:        "-73.99",
+			expected: "-73.99",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			s:        "abc",
+			expected: "abc",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := coordinate(tt.s)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewMETNorway(t *testing.T) {
+	lat := "123.45"
+	long := "67.890"
+
+	tests := []struct {
+		timezone  string
+		userAgent string
+		cache     Cache
+
+		expectedNil       bool
+		expectedUserAgent string
+	}{
+		// TEST0 {{{
+		{
+			timezone:  "Asia/Tokyo",
+			userAgent: "",
+
+			expectedUserAgent: defaultUserAgent,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			timezone:  "Europe/Oslo",
+			userAgent: "test/1.0 test@example.com",
+			cache:     &memoryCache{},
+
+			expectedUserAgent: "test/1.0 test@example.com",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			timezone: "",
+
+			expectedNil: true,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			timezone: "Local",
+
+			expectedNil: true,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			timezone: "Unknown/Zone",
+
+			expectedNil: true,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			fore := NewMETNorway(lat, long, tt.timezone, tt.userAgent, tt.cache)
+			if tt.expectedNil {
+				if fore != nil {
+					t.Fatal("Expected nil, but not.")
+				}
+				return
+			}
+			if fore == nil {
+				t.Fatal("function returns nil")
+			}
+
+			f, ok := fore.(*metNorway)
+			if !ok {
+				t.Fatal("Expected metNorway instance, but not.")
+			}
+
+			if f.lat != lat || f.long != long {
+				t.Fatalf("Expected location is %s,%s, but it's %s,%s.", lat, long, f.lat, f.long)
+			}
+
+			if f.location.String() != tt.timezone {
+				t.Fatalf("Expected location is %s, but it's %s.", tt.timezone, f.location)
+			}
+
+			if f.userAgent != tt.expectedUserAgent {
+				t.Fatalf("Expected User-Agent is %s, but it's %s.", tt.expectedUserAgent, f.userAgent)
+			}
+
+			expectedURL := fmt.Sprintf("%s/weatherapi/locationforecast/2.0/compact", metNorwayAPIBase)
+			if f.url == nil {
+				t.Fatal("url is nil")
+			} else if f.url.String() != expectedURL {
+				t.Fatalf("Expected url is %s, but it's %s.", expectedURL, f.url.String())
+			}
+
+			if f.cache != tt.cache {
+				t.Fatalf("Expected cache is %v, but it's %v.", tt.cache, f.cache)
+			}
+
+			if f.httpClient == nil {
+				t.Fatal("httpClient is nil")
+			}
+		})
+	}
+}
+
+type memoryCache struct {
+	mutex  sync.Mutex
+	values map[string][]byte
+}
+
+func (c *memoryCache) Get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	v, ok := c.values[key]
+	return v, ok
+}
+
+func (c *memoryCache) Set(key string, value []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.values == nil {
+		c.values = map[string][]byte{}
+	}
+	c.values[key] = value
+
+	return nil
+}
+
+const (
+	metNorwayLastModified = "Tue, 30 Jan 2018 14:47:21 GMT"
+	metNorwayExpires      = "Tue, 30 Jan 2018 15:20:00 GMT"
+)
+
+func metNorwayFunc(requests *int, resStatus int, response string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if ua := r.Header.Get("User-Agent"); ua != "test/1.0 test@example.com" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprintf(w, "Unexpected request: `User-Agent` header = %s", ua)
+			return
+		}
+
+		if q := r.URL.Query(); q.Get("lat") != "34.9208" || q.Get("lon") != "136.9886" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "Unexpected request: query = %s", r.URL.RawQuery)
+			return
+		}
+
+		w.Header().Set("Expires", metNorwayExpires)
+		w.Header().Set("Last-Modified", metNorwayLastModified)
+		if r.Header.Get("If-Modified-Since") == metNorwayLastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(resStatus)
+		w.Write([]byte(response))
+	}
+}
+
+func TestMETNorway_Get(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+	day0 := time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo)
+	day1 := day0.AddDate(0, 0, 1)
+	day2 := day0.AddDate(0, 0, 2)
+
+	tests := []struct {
+		units Units
+
+		resStatus  int
+		resMessage string
+
+		expectedHourly map[int]HourlyPoint
+		expectedHours  int
+		expectedDaily  []DailyPoint
+		expectedError  error
+	}{
+		// TEST0 {{{
+		{
+			units: UnitsSI,
+
+			resStatus:  http.StatusOK,
+			resMessage: readFile("testdata/metno/get00.json"),
+
+			expectedHourly: map[int]HourlyPoint{
+				0: {
+					Time:                day0,
+					Weather:             WeatherClearNight,
+					Summary:             "Clear sky",
+					Temperature:         -1.5,
+					ApparentTemperature: apparentTemperature(-1.5, 60, 3),
+					PrecipProbability:   math.NaN(),
+					PrecipAccumulation:  0,
+				},
+				18: {
+					Time:                day0.Add(18 * time.Hour),
+					Weather:             WeatherSnow,
+					Summary:             "Light snow",
+					Temperature:         4,
+					ApparentTemperature: apparentTemperature(4, 78, 3),
+					PrecipProbability:   math.NaN(),
+					PrecipAccumulation:  snowAccumulation(0.5),
+				},
+				31: {
+					Time:                day1.Add(12 * time.Hour),
+					Weather:             WeatherRain,
+					Summary:             "Rain",
+					Temperature:         6,
+					ApparentTemperature: apparentTemperature(6, 80, 5),
+					PrecipProbability:   math.NaN(),
+					PrecipAccumulation:  0,
+				},
+			},
+			expectedHours: 34,
+			expectedDaily: []DailyPoint{
+				{
+					Time:                        day0,
+					Weather:                     WeatherPartlyCloudyDay,
+					Summary:                     "Fair",
+					TemperatureHigh:             6,
+					TemperatureHighTime:         day0.Add(14 * time.Hour),
+					TemperatureLow:              -2,
+					TemperatureLowTime:          day0.Add(2 * time.Hour),
+					ApparentTemperatureHigh:     apparentTemperature(6, 74, 3),
+					ApparentTemperatureHighTime: day0.Add(14 * time.Hour),
+					ApparentTemperatureLow:      apparentTemperature(-2, 62, 3),
+					ApparentTemperatureLowTime:  day0.Add(2 * time.Hour),
+					PrecipProbability:           math.NaN(),
+					PrecipAccumulation:          3,
+				},
+				{
+					Time:                        day1,
+					Weather:                     WeatherSnow,
+					Summary:                     "Heavy snow showers and thunder",
+					TemperatureHigh:             7,
+					TemperatureHighTime:         day1.Add(18 * time.Hour),
+					TemperatureLow:              -2,
+					TemperatureLowTime:          day1.Add(2 * time.Hour),
+					ApparentTemperatureHigh:     apparentTemperature(7, 80, 5),
+					ApparentTemperatureHighTime: day1.Add(18 * time.Hour),
+					ApparentTemperatureLow:      apparentTemperature(-2, 86, 3),
+					ApparentTemperatureLowTime:  day1.Add(2 * time.Hour),
+					PrecipProbability:           math.NaN(),
+					PrecipAccumulation:          3,
+				},
+				{
+					Time:                        day2,
+					Weather:                     WeatherClearNight,
+					Summary:                     "Clear sky",
+					TemperatureHigh:             8,
+					TemperatureHighTime:         day2,
+					TemperatureLow:              8,
+					TemperatureLowTime:          day2,
+					ApparentTemperatureHigh:     apparentTemperature(8, 80, 5),
+					ApparentTemperatureHighTime: day2,
+					ApparentTemperatureLow:      apparentTemperature(8, 80, 5),
+					ApparentTemperatureLowTime:  day2,
+					PrecipProbability:           math.NaN(),
+					PrecipAccumulation:          0,
+				},
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			units: UnitsUS,
+
+			resStatus:  http.StatusOK,
+			resMessage: readFile("testdata/metno/get00.json"),
+
+			expectedHourly: map[int]HourlyPoint{
+				18: {
+					Time:                day0.Add(18 * time.Hour),
+					Weather:             WeatherSnow,
+					Summary:             "Light snow",
+					Temperature:         fahrenheit(4),
+					ApparentTemperature: fahrenheit(apparentTemperature(4, 78, 3)),
+					PrecipProbability:   math.NaN(),
+					PrecipAccumulation:  inches(snowAccumulation(0.5)),
+				},
+			},
+			expectedHours: 34,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			units: UnitsSI,
+
+			resStatus:  http.StatusTooManyRequests,
+			resMessage: "Too Many Requests\n",
+
+			expectedError: metNorwayError{
+				Code:    http.StatusTooManyRequests,
+				Message: "Too Many Requests",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			requests := 0
+			server := httptest.NewTLSServer(http.HandlerFunc(metNorwayFunc(&requests, tt.resStatus, tt.resMessage)))
+			defer server.Close()
+
+			var err error
+			f := &metNorway{
+				lat:       "34.920812",
+				long:      "136.98864",
+				userAgent: "test/1.0 test@example.com",
+				location:  tokyo,
+				now:       time.Now,
+			}
+			f.url, err = neturl.Parse(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.httpClient = server.Client()
+
+			res, err := f.Get(LangEn, tt.units)
+			if err != nil {
+				if tt.expectedError == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err.Error() != tt.expectedError.Error() {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expectedError, err)
+				}
+				return
+			}
+
+			if tt.expectedError != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+				return
+			}
+
+			if len(res.Hourly) != tt.expectedHours {
+				t.Fatalf("Expected %d hours, but got %d", tt.expectedHours, len(res.Hourly))
+			}
+			for i, expected := range tt.expectedHourly {
+				if !sameValue(res.Hourly[i], expected) {
+					t.Errorf("Expected to get [%+v], but got [%+v]", expected, res.Hourly[i])
+				}
+			}
+			if tt.expectedDaily != nil && !sameValue(res.Daily, tt.expectedDaily) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expectedDaily, res.Daily)
+			}
+		})
+	}
+}
+
+// 実行するマシンのタイムゾーンと予報地点のタイムゾーンが異なる場合
+//
+// time.Local を書き換えるので並行に実行しない。
+func TestMETNorway_Get_location(t *testing.T) {
+	local := time.Local
+	time.Local = loadLocation("America/New_York")
+	defer func() {
+		time.Local = local
+	}()
+
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(metNorwayFunc(&requests, http.StatusOK, readFile("testdata/metno/get00.json"))))
+	defer server.Close()
+
+	fore := NewMETNorway("34.9208", "136.9886", "Asia/Tokyo", "test/1.0 test@example.com", nil)
+	if fore == nil {
+		t.Fatal("function returns nil")
+	}
+
+	var err error
+	f := fore.(*metNorway)
+	f.url, err = neturl.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.httpClient = server.Client()
+
+	res, err := f.Get(LangEn, UnitsSI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokyo := loadLocation("Asia/Tokyo")
+	if res.Location.String() != tokyo.String() {
+		t.Errorf("Expected location is %s, but it's %s", tokyo, res.Location)
+	}
+
+	expected := time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo)
+	if len(res.Daily) == 0 {
+		t.Fatal("Expected daily forecast, but got nothing")
+	}
+	if !res.Daily[0].Time.Equal(expected) || res.Daily[0].Time.Location().String() != tokyo.String() {
+		t.Errorf("Expected the first day is %s, but it's %s", expected, res.Daily[0].Time)
+	}
+}
+
+func TestMETNorway_Get_cache(t *testing.T) {
+	expires, err := http.ParseTime(metNorwayExpires)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(metNorwayFunc(&requests, http.StatusOK, readFile("testdata/metno/get00.json"))))
+	defer server.Close()
+
+	now := expires.Add(-10 * time.Minute)
+	f := &metNorway{
+		lat:       "34.9208",
+		long:      "136.9886",
+		userAgent: "test/1.0 test@example.com",
+		location:  loadLocation("Asia/Tokyo"),
+		cache:     &memoryCache{},
+		now: func() time.Time {
+			return now
+		},
+	}
+	f.url, err = neturl.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.httpClient = server.Client()
+
+	tests := []struct {
+		now time.Time
+
+		expectedRequests int
+	}{
+		// TEST0 {{{
+		{
+			now: expires.Add(-10 * time.Minute),
+
+			expectedRequests: 1, // 200
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			now: expires.Add(-1 * time.Minute),
+
+			expectedRequests: 1, // Expires まではリクエストしない
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			now: expires.Add(1 * time.Minute),
+
+			expectedRequests: 2, // 304
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		now = tt.now
+
+		res, err := f.Get(LangEn, UnitsSI)
+		if err != nil {
+			t.Fatalf("%02d: Expected no error occurred, but it occurred (%v)", i, err)
+		}
+
+		if requests != tt.expectedRequests {
+			t.Errorf("%02d: Expected %d requests, but got %d", i, tt.expectedRequests, requests)
+		}
+
+		if len(res.Hourly) != 34 {
+			t.Errorf("%02d: Expected %d hours, but got %d", i, 34, len(res.Hourly))
+		}
+	}
+}
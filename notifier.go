@@ -0,0 +1,221 @@
+package weatherline
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"math"
+	"time"
+)
+
+// forecastTemplate : HTML 形式の予報 (気象警報の一覧と時間別予報・日別予報の表)
+var forecastTemplate = template.Must(template.New("forecast").Funcs(template.FuncMap{
+	"time":  FormatTime,
+	"icon":  IconText,
+	"alert": AlertText,
+}).Parse(`
+{{- with .Alerts -}}
+<ul>
+{{- range .}}
+<li><strong>{{alert .}}</strong>{{with .Description}}<br>{{.}}{{end}}</li>
+{{- end}}
+</ul>
+{{end -}}
+{{- with .Hourly -}}
+<h2>{{$.Date.Format "01/02 (Mon)"}}</h2>
+<table>
+<tr><th>Time</th><th></th><th>Temp.</th><th>Feels like</th><th>Precip.</th></tr>
+{{- range .}}
+<tr><td>{{.Time.Format "15:04"}}</td><td>{{icon .Weather}}</td><td>{{$.Format.Temperature .Temperature}}</td><td>{{$.Format.Temperature .ApparentTemperature}}</td><td>{{$.Format.Precip .Weather .PrecipProbability .PrecipAccumulation}}</td></tr>
+{{- end}}
+</table>
+{{end -}}
+{{- with .Daily -}}
+<table>
+<tr><th>Date</th><th></th><th>High</th><th>Low</th><th>Precip.</th><th></th></tr>
+{{- range .}}
+<tr><td>{{.Time.Format "01/02 (Mon)"}}</td><td>{{icon .Weather}}</td><td>{{$.Format.Temperature .TemperatureHigh}}/{{$.Format.Temperature .ApparentTemperatureHigh}} ({{time "15:04" .ApparentTemperatureHighTime}})</td><td>{{$.Format.Temperature .TemperatureLow}}/{{$.Format.Temperature .ApparentTemperatureLow}} ({{time "15:04" .ApparentTemperatureLowTime}})</td><td>{{$.Format.Precip .Weather .PrecipProbability .PrecipAccumulation}}</td><td>{{.Summary}}</td></tr>
+{{- end}}
+</table>
+{{end -}}
+`))
+
+// Message : 通知する天気予報
+//
+// Report が nil で Alerts がある場合は気象警報だけの通知になる。
+type Message struct {
+	Date   time.Time // 予報の対象日 (予報地点のタイムゾーン)
+	Days   int       // 対象日の翌日から何日分の日別予報を通知するか
+	Text   string    // テキスト形式の予報
+	Report *Report
+	Alerts []Alert // 対象日に有効な気象警報
+}
+
+// Notifier : 通知先 client interface
+type Notifier interface {
+	Notify(*Message) error
+}
+
+// payloader : リクエストボディを作る Notifier
+type payloader interface {
+	payload(*Message) ([]byte, error)
+}
+
+// Preview : 通知せずに Notifier が送信する内容を返す
+//
+// リクエストボディを作る Notifier はそのボディを、それ以外はテキスト形式の予報を返す。
+// 宛先ごとに送信する Notifier は宛先ごとのボディを改行区切りで返す。
+func Preview(n Notifier, msg *Message) ([]byte, error) {
+	switch n := n.(type) {
+	case payloader:
+		return n.payload(msg)
+	case *lineMessaging:
+		payloads := [][]byte{}
+		for _, to := range n.to {
+			b, err := n.payload(to, msg)
+			if err != nil {
+				return nil, err
+			}
+			payloads = append(payloads, b)
+		}
+
+		return bytes.Join(payloads, []byte("\n")), nil
+	default:
+		return []byte(msg.Text), nil
+	}
+}
+
+// day : 日付の 0 時
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// HourlyPoints : 対象日の時間別予報
+func (m *Message) HourlyPoints() []HourlyPoint {
+	if m.Report == nil {
+		return nil
+	}
+
+	date := day(m.Date)
+
+	var points []HourlyPoint
+	for _, p := range m.Report.Hourly {
+		if day(p.Time.In(date.Location())).Equal(date) {
+			points = append(points, p)
+		}
+	}
+
+	return points
+}
+
+// DailyPoints : 対象日の翌日から Days 日分の日別予報
+func (m *Message) DailyPoints() []DailyPoint {
+	if m.Report == nil {
+		return nil
+	}
+
+	date := day(m.Date)
+	to := date.AddDate(0, 0, m.Days)
+
+	var points []DailyPoint
+	for _, p := range m.Report.Daily {
+		d := day(p.Time.In(date.Location()))
+		if d.After(date) && !d.After(to) {
+			points = append(points, p)
+		}
+	}
+
+	return points
+}
+
+// Format : 予報値の単位系に合わせた Formatter (予報がなければ si とみなす)
+func (m *Message) Format() Formatter {
+	if m.Report == nil {
+		return NewFormatter(UnitsUnknown)
+	}
+
+	return NewFormatter(m.Report.Units)
+}
+
+// Title : 件名などに使う見出し
+func (m *Message) Title() string {
+	if m.Report == nil && len(m.Alerts) > 0 {
+		return fmt.Sprintf("Weather alert %s", m.Date.Format("01/02 (Mon)"))
+	}
+
+	return fmt.Sprintf("Weather forecast %s", m.Date.Format("01/02 (Mon)"))
+}
+
+// forecastHTML : テンプレートで HTML 形式の予報を作る (予報も気象警報もなければ空文字)
+func forecastHTML(t *template.Template, msg *Message) (string, error) {
+	data := struct {
+		Date   time.Time
+		Alerts []Alert
+		Hourly []HourlyPoint
+		Daily  []DailyPoint
+		Format Formatter
+	}{
+		Date:   msg.Date,
+		Alerts: msg.Alerts,
+		Hourly: msg.HourlyPoints(),
+		Daily:  msg.DailyPoints(),
+		Format: msg.Format(),
+	}
+	if len(data.Alerts) == 0 && len(data.Hourly) == 0 && len(data.Daily) == 0 {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// FormatValue : 値が得られない (NaN) 場合は "-" を返す
+func FormatValue(format string, v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+
+	return fmt.Sprintf(format, v)
+}
+
+// FormatTime : 時刻が得られない (ゼロ値) 場合は "-" を返す
+func FormatTime(layout string, t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Format(layout)
+}
+
+// IconText : 天気種別の絵文字 (不明な場合は IconUnknown)
+func IconText(w Weather) string {
+	icon, ok := w.Icon()
+	if !ok {
+		icon = IconUnknown
+	}
+
+	return string(icon)
+}
+
+// WeatherText : 天気種別の絵文字 (不明な場合は summary、それもなければ IconUnknown)
+func WeatherText(w Weather, summary string) string {
+	if _, ok := w.Icon(); !ok && summary != "" {
+		return summary
+	}
+
+	return IconText(w)
+}
+
+// AlertText : 気象警報の見出し (失効時刻が分かれば併記する)
+func AlertText(a Alert) string {
+	s := "⚠ " + a.Title
+	if !a.Expires.IsZero() {
+		s += a.Expires.Format(" (~01/02 15:04)")
+	}
+
+	return s
+}
@@ -0,0 +1,434 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMessage_HourlyPoints(t *testing.T) {
+	report := unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report()
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		msg Message
+
+		expectedHours int
+		expectedFirst time.Time
+	}{
+		// TEST0 {{{
+		{
+			msg: Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Report: report,
+			},
+
+			expectedHours: 24,
+			expectedFirst: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: Message{
+				Date:   time.Date(2018, 3, 1, 0, 0, 0, 0, tokyo),
+				Report: report,
+			},
+
+			expectedHours: 0,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			msg: Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+			},
+
+			expectedHours: 0,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.msg.HourlyPoints()
+			if len(actual) != tt.expectedHours {
+				t.Fatalf("Expected %d hours, but got %d", tt.expectedHours, len(actual))
+			}
+			if len(actual) > 0 && !actual[0].Time.Equal(tt.expectedFirst) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expectedFirst, actual[0].Time)
+			}
+		})
+	}
+}
+
+func TestMessage_DailyPoints(t *testing.T) {
+	report := unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report()
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		msg Message
+
+		expected []time.Time
+	}{
+		// TEST0 {{{
+		{
+			msg: Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Report: report,
+			},
+
+			expected: []time.Time{
+				time.Date(2018, 2, 1, 0, 0, 0, 0, tokyo),
+				time.Date(2018, 2, 2, 0, 0, 0, 0, tokyo),
+				time.Date(2018, 2, 3, 0, 0, 0, 0, tokyo),
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   1,
+				Report: report,
+			},
+
+			expected: []time.Time{
+				time.Date(2018, 2, 1, 0, 0, 0, 0, tokyo),
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			msg: Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Report: report,
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.msg.DailyPoints()
+			if len(actual) != len(tt.expected) {
+				t.Fatalf("Expected %d days, but got %d", len(tt.expected), len(actual))
+			}
+			for i, p := range actual {
+				if !p.Time.Equal(tt.expected[i]) {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected[i], p.Time)
+				}
+			}
+		})
+	}
+}
+
+func TestMessage_Title(t *testing.T) {
+	date := time.Date(2018, 1, 31, 0, 0, 0, 0, loadLocation("Asia/Tokyo"))
+	alerts := []Alert{{Title: "Heavy Snow Warning"}}
+
+	tests := []struct {
+		msg Message
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			msg: Message{Date: date},
+
+			expected: "Weather forecast 01/31 (Wed)",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: Message{Date: date, Alerts: alerts},
+
+			expected: "Weather alert 01/31 (Wed)",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			msg: Message{Date: date, Report: &Report{}, Alerts: alerts},
+
+			expected: "Weather forecast 01/31 (Wed)",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.msg.Title()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	msg := &Message{
+		Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, loadLocation("Asia/Tokyo")),
+		Days:   3,
+		Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+		Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+	}
+
+	lineMessagingPayload := func(to string) string {
+		b, err := (&lineMessaging{}).payload(to, msg)
+		if err != nil {
+			panic(err)
+		}
+
+		return string(b)
+	}
+
+	tests := []struct {
+		notifier Notifier
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			notifier: NewLineNotify("XXXXX"),
+
+			expected: msg.Text,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			notifier: NewWriter(nil),
+
+			expected: msg.Text,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			notifier: NewSlack("https://hooks.slack.com/services/T000/B000/XXXX"),
+
+			expected: readFile("testdata/slack/payload00.json"),
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			notifier: NewDiscord("https://discord.com/api/webhooks/000/XXXX"),
+
+			expected: readFile("testdata/discord/payload00.json"),
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			notifier: NewLineMessaging("XXXXX", []string{"U0001", "U0002"}),
+
+			expected: lineMessagingPayload("U0001") + "\n" + lineMessagingPayload("U0002"),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			b, err := Preview(tt.notifier, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := string(b)
+			if json.Valid(b) {
+				actual = indentJSON(b)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// alertMessage : 気象警報と1時間分の予報を通知するメッセージ
+func alertMessage(loc *time.Location) *Message {
+	return &Message{
+		Date: time.Date(2018, 1, 31, 0, 0, 0, 0, loc),
+		Text: "\n⚠ 大雨警報 (~02/01 06:00)\n\n01/31\n",
+		Report: &Report{
+			Location: loc,
+			Hourly: []HourlyPoint{
+				{
+					Time:                time.Date(2018, 1, 31, 9, 0, 0, 0, loc),
+					Weather:             WeatherRain,
+					Temperature:         5.2,
+					ApparentTemperature: 3.1,
+					PrecipProbability:   0.6,
+					PrecipAccumulation:  math.NaN(),
+				},
+			},
+		},
+		Alerts: []Alert{
+			{Title: "大雨警報", Expires: time.Date(2018, 2, 1, 6, 0, 0, 0, loc)},
+		},
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		format string
+		v      float64
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			format:   "%.1f℃",
+			v:        12.34,
+			expected: "12.3℃",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			format:   "%.0f%%",
+			v:        math.NaN(),
+			expected: "-",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := FormatValue(tt.format, tt.v)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	tests := []struct {
+		layout string
+		t      time.Time
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			layout:   "15:04",
+			t:        time.Date(2018, 1, 31, 14, 0, 0, 0, time.UTC),
+			expected: "14:00",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			layout:   "15:04",
+			expected: "-",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := FormatTime(tt.layout, tt.t)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWeatherText(t *testing.T) {
+	tests := []struct {
+		weather Weather
+		summary string
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			weather: WeatherRain,
+			summary: "Rain",
+
+			expected: "☔",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			weather: WeatherUnknown,
+			summary: "Thunderstorm",
+
+			expected: "Thunderstorm",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			weather: WeatherUnknown,
+
+			expected: string(IconUnknown),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := WeatherText(tt.weather, tt.summary)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAlertText(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		alert Alert
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			alert: Alert{Title: "大雨警報", Expires: time.Date(2018, 2, 1, 6, 0, 0, 0, tokyo)},
+
+			expected: "⚠ 大雨警報 (~02/01 06:00)",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			alert: Alert{Title: "乾燥注意報"},
+
+			expected: "⚠ 乾燥注意報",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := AlertText(tt.alert)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
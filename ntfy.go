@@ -0,0 +1,134 @@
+package weatherline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// ntfy の優先度 (1: min 〜 5: max)
+var ntfyPriorities = map[pushLevel]int{
+	pushLevelDefault: 3,
+	pushLevelHigh:    4,
+}
+
+// ntfy のタグ (絵文字の shortcode)
+var ntfyTags = map[Weather]string{
+	WeatherClearDay:          "sunny",
+	WeatherClearNight:        "crescent_moon",
+	WeatherRain:              "umbrella",
+	WeatherSnow:              "snowflake",
+	WeatherSleet:             "cloud_with_rain",
+	WeatherWind:              "dash",
+	WeatherFog:               "fog",
+	WeatherCloudy:            "cloud",
+	WeatherPartlyCloudyDay:   "partly_sunny",
+	WeatherPartlyCloudyNight: "cloud",
+}
+
+type ntfyError struct {
+	Code    int    `json:"code"`
+	HTTP    int    `json:"http"`
+	Message string `json:"error"`
+}
+
+func (e ntfyError) Error() string {
+	return fmt.Sprintf("%d: %s", e.HTTP, e.Message)
+}
+
+type ntfyRequest struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type ntfy struct {
+	topic     string
+	token     string
+	threshold float64
+
+	url        string
+	httpClient *http.Client
+}
+
+// NewNtfy : Create Notifier instance for ntfy
+//
+// token が空の場合は認証しない。
+// 降水確率が threshold 以上の時間があるか雪が降る場合は優先度を上げる。
+func NewNtfy(serverURL, topic, token string, threshold float64) Notifier {
+	return &ntfy{
+		topic:     topic,
+		token:     token,
+		threshold: threshold,
+
+		url:        serverURL,
+		httpClient: &http.Client{},
+	}
+}
+
+// payload : リクエストボディ
+func (n *ntfy) payload(msg *Message) ([]byte, error) {
+	level, weather := pushAlert(msg, n.threshold)
+
+	tags := []string{}
+	if level == pushLevelHigh {
+		tags = append(tags, "warning")
+	}
+	if tag, ok := ntfyTags[weather]; ok {
+		tags = append(tags, tag)
+	}
+
+	return json.Marshal(ntfyRequest{
+		Topic:    n.topic,
+		Title:    msg.Title(),
+		Message:  strings.TrimSpace(msg.Text),
+		Priority: ntfyPriorities[level],
+		Tags:     tags,
+	})
+}
+
+// Notify : Notifier.Notify の実装
+func (n *ntfy) Notify(msg *Message) error {
+	body, err := n.payload(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", n.token))
+	}
+
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	response := ntfyError{}
+	if err := json.Unmarshal(b, &response); err != nil || response.Message == "" {
+		response.Message = strings.TrimSpace(string(b))
+	}
+	response.HTTP = res.StatusCode
+
+	return response
+}
@@ -0,0 +1,272 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNtfyError_Error(t *testing.T) {
+	err := ntfyError{
+		Code:    40301,
+		HTTP:    403,
+		Message: "forbidden",
+	}
+
+	expected := "403: forbidden"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestNewNtfy(t *testing.T) {
+	serverURL := "https://ntfy.example.com"
+	topic := "weather"
+	token := "tk_XXXX"
+	threshold := 0.6
+
+	notifier := NewNtfy(serverURL, topic, token, threshold)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*ntfy)
+	if !ok {
+		t.Fatal("Expected ntfy instance, but not.")
+	}
+
+	if n.url != serverURL {
+		t.Fatalf("Expected url is %s, but it's %s.", serverURL, n.url)
+	}
+
+	if n.topic != topic {
+		t.Fatalf("Expected topic is %s, but it's %s.", topic, n.topic)
+	}
+
+	if n.token != token {
+		t.Fatalf("Expected token is %s, but it's %s.", token, n.token)
+	}
+
+	if n.threshold != threshold {
+		t.Fatalf("Expected threshold is %f, but it's %f.", threshold, n.threshold)
+	}
+
+	if n.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+}
+
+func TestNtfy_payload(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+	date := time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo)
+
+	tests := []struct {
+		msg       *Message
+		threshold float64
+
+		expected ntfyRequest
+	}{
+		// TEST0 {{{
+		{
+			msg: &Message{
+				Date:   date,
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+			},
+			threshold: 0.5,
+
+			expected: ntfyRequest{
+				Topic:    "weather",
+				Title:    "Weather forecast 01/31 (Wed)",
+				Message:  strings.TrimSpace(readFile("testdata/weatherline/cmd/run00.txt")),
+				Priority: 3,
+				Tags:     []string{"sunny"},
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: &Message{
+				Date: date,
+				Text: "\n01/31\n",
+				Report: &Report{
+					Hourly: []HourlyPoint{
+						{Time: date.Add(9 * time.Hour), Weather: WeatherSnow, PrecipProbability: 0.3},
+					},
+				},
+			},
+			threshold: 0.5,
+
+			expected: ntfyRequest{
+				Topic:    "weather",
+				Title:    "Weather forecast 01/31 (Wed)",
+				Message:  "01/31",
+				Priority: 4,
+				Tags:     []string{"warning", "snowflake"},
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			msg: &Message{
+				Date: date,
+				Text: "\n01/31\n",
+			},
+			threshold: 0.5,
+
+			expected: ntfyRequest{
+				Topic:    "weather",
+				Title:    "Weather forecast 01/31 (Wed)",
+				Message:  "01/31",
+				Priority: 3,
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			n := &ntfy{topic: "weather", threshold: tt.threshold}
+			b, err := n.payload(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := ntfyRequest{}
+			if err := json.Unmarshal(b, &actual); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected to get %+v, but got %+v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func ntfyFunc(token string, resStatus int, resBody string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			fmt.Fprint(w, `{"code":40501,"http":405,"error":"method not allowed"}`)
+			return
+		}
+
+		if token != "" && r.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", token) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"code":40101,"http":401,"error":"unauthorized"}`)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil || !json.Valid(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"code":40024,"http":400,"error":"invalid request: request body must be message JSON"}`)
+			return
+		}
+
+		w.WriteHeader(resStatus)
+		fmt.Fprint(w, resBody)
+	}
+}
+
+func TestNtfy_Notify(t *testing.T) {
+	tests := []struct {
+		serverToken string
+		token       string
+		resStatus   int
+		resBody     string
+
+		expected error
+	}{
+		// TEST0 {{{
+		{
+			serverToken: "",
+			token:       "",
+			resStatus:   http.StatusOK,
+			resBody:     `{"id":"xxxx","event":"message","topic":"weather"}`,
+
+			expected: nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			serverToken: "tk_XXXX",
+			token:       "tk_XXXX",
+			resStatus:   http.StatusOK,
+			resBody:     `{"id":"xxxx","event":"message","topic":"weather"}`,
+
+			expected: nil,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			serverToken: "tk_XXXX",
+			token:       "tk_YYYY",
+			resStatus:   http.StatusOK,
+			resBody:     `{"id":"xxxx","event":"message","topic":"weather"}`,
+
+			expected: ntfyError{
+				Code:    40101,
+				HTTP:    http.StatusUnauthorized,
+				Message: "unauthorized",
+			},
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			serverToken: "",
+			token:       "",
+			resStatus:   http.StatusBadGateway,
+			resBody:     "Bad Gateway\n",
+
+			expected: ntfyError{
+				HTTP:    http.StatusBadGateway,
+				Message: "Bad Gateway",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(ntfyFunc(tt.serverToken, tt.resStatus, tt.resBody)))
+			defer server.Close()
+
+			n := &ntfy{
+				topic:     "weather",
+				token:     tt.token,
+				threshold: DefaultPushThreshold,
+
+				url:        server.URL,
+				httpClient: server.Client(),
+			}
+
+			err := n.Notify(&Message{Text: "TEST"})
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err != tt.expected {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+		})
+	}
+}
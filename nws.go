@@ -0,0 +1,388 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	nwsAPIBase = "https://api.weather.gov"
+)
+
+type nwsError struct {
+	Code   int    `json:"status"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+func (e nwsError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%d: %s", e.Code, e.Detail)
+	}
+
+	return fmt.Sprintf("%d: %s", e.Code, e.Title)
+}
+
+var nwsIcons = map[string]Weather{
+	"skc":             WeatherClearDay,
+	"few":             WeatherClearDay,
+	"sct":             WeatherPartlyCloudyDay,
+	"bkn":             WeatherPartlyCloudyDay,
+	"ovc":             WeatherCloudy,
+	"wind_skc":        WeatherWind,
+	"wind_few":        WeatherWind,
+	"wind_sct":        WeatherWind,
+	"wind_bkn":        WeatherWind,
+	"wind_ovc":        WeatherWind,
+	"snow":            WeatherSnow,
+	"blizzard":        WeatherSnow,
+	"rain_snow":       WeatherSleet,
+	"rain_sleet":      WeatherSleet,
+	"snow_sleet":      WeatherSleet,
+	"fzra":            WeatherSleet,
+	"rain_fzra":       WeatherSleet,
+	"snow_fzra":       WeatherSleet,
+	"sleet":           WeatherSleet,
+	"rain":            WeatherRain,
+	"rain_showers":    WeatherRain,
+	"rain_showers_hi": WeatherRain,
+	"tsra":            WeatherRain,
+	"tsra_sct":        WeatherRain,
+	"tsra_hi":         WeatherRain,
+	"tornado":         WeatherWind,
+	"hurricane":       WeatherWind,
+	"tropical_storm":  WeatherWind,
+	"dust":            WeatherFog,
+	"smoke":           WeatherFog,
+	"haze":            WeatherFog,
+	"fog":             WeatherFog,
+	"hot":             WeatherClearDay,
+	"cold":            WeatherClearDay,
+}
+
+// nwsWeather : アイコンの URL (例: https://api.weather.gov/icons/land/night/tsra,40/rain?size=small) を天気種別に変換する
+func nwsWeather(icon string) Weather {
+	u, err := url.Parse(icon)
+	if err != nil {
+		return WeatherUnknown
+	}
+
+	// .../{day|night}/{code}[,{pop}][/{code}[,{pop}]]
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, p := range parts {
+		if p != "day" && p != "night" || i+1 >= len(parts) {
+			continue
+		}
+
+		w, ok := nwsIcons[strings.Split(parts[i+1], ",")[0]]
+		if !ok {
+			return WeatherUnknown
+		}
+		if p == "night" {
+			return w.night()
+		}
+		return w
+	}
+
+	return WeatherUnknown
+}
+
+// nwsWindSpeed : 風速 (例: "10 mph", "5 to 10 km/h") を m/s に変換する
+func nwsWindSpeed(s string) float64 {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return math.NaN()
+	}
+
+	v, err := strconv.ParseFloat(fields[len(fields)-2], 64)
+	if err != nil {
+		return math.NaN()
+	}
+
+	switch fields[len(fields)-1] {
+	case "mph":
+		return v * 0.44704
+	case "km/h":
+		return v / 3.6
+	default:
+		return math.NaN()
+	}
+}
+
+type nwsValue struct {
+	Value *float64 `json:"value"`
+}
+
+func (v nwsValue) float() float64 {
+	if v.Value == nil {
+		return math.NaN()
+	}
+
+	return *v.Value
+}
+
+type nwsPeriod struct {
+	StartTime                  time.Time `json:"startTime"`
+	IsDaytime                  bool      `json:"isDaytime"`
+	Temperature                float64   `json:"temperature"`
+	TemperatureUnit            string    `json:"temperatureUnit"`
+	ProbabilityOfPrecipitation nwsValue  `json:"probabilityOfPrecipitation"` // %
+	RelativeHumidity           nwsValue  `json:"relativeHumidity"`           // %
+	WindSpeed                  string    `json:"windSpeed"`
+	Icon                       string    `json:"icon"`
+	ShortForecast              string    `json:"shortForecast"`
+}
+
+type nwsForecast struct {
+	Properties struct {
+		Periods []nwsPeriod `json:"periods"`
+	} `json:"properties"`
+}
+
+// nwsGrid : /points で求めた予報の URL
+type nwsGrid struct {
+	Forecast       string `json:"forecast"`
+	ForecastHourly string `json:"forecastHourly"`
+	TimeZone       string `json:"timeZone"`
+}
+
+func nwsReport(loc *time.Location, hourly, daily *nwsForecast) *Report {
+	report := &Report{
+		Location: loc,
+	}
+	for _, p := range hourly.Properties.Periods {
+		apparent := math.NaN()
+		if humidity, wind := p.RelativeHumidity.float(), nwsWindSpeed(p.WindSpeed); !math.IsNaN(humidity) && !math.IsNaN(wind) {
+			if p.TemperatureUnit == "F" {
+				apparent = fahrenheit(apparentTemperature((p.Temperature-32)*5/9, humidity, wind))
+			} else {
+				apparent = apparentTemperature(p.Temperature, humidity, wind)
+			}
+		}
+
+		report.Hourly = append(report.Hourly, HourlyPoint{
+			Time:                p.StartTime.In(loc),
+			Weather:             nwsWeather(p.Icon),
+			Summary:             p.ShortForecast,
+			Temperature:         p.Temperature,
+			ApparentTemperature: apparent,
+			PrecipProbability:   p.ProbabilityOfPrecipitation.float() / 100,
+			PrecipAccumulation:  math.NaN(),
+		})
+	}
+
+	// 昼と夜の12時間ごとの予報を1日にまとめる
+	var days []*DailyPoint
+	for _, p := range daily.Properties.Periods {
+		t := p.StartTime.In(loc)
+		from := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+
+		var d *DailyPoint
+		if len(days) > 0 && days[len(days)-1].Time.Equal(from) {
+			d = days[len(days)-1]
+		} else {
+			d = &DailyPoint{
+				Time:              from,
+				TemperatureHigh:   math.NaN(),
+				TemperatureLow:    math.NaN(),
+				PrecipProbability: math.NaN(),
+			}
+			days = append(days, d)
+		}
+
+		if p.IsDaytime {
+			d.Weather = nwsWeather(p.Icon)
+			d.Summary = p.ShortForecast
+			d.TemperatureHigh = p.Temperature
+		} else {
+			if d.Weather == WeatherUnknown {
+				d.Weather = nwsWeather(p.Icon)
+				d.Summary = p.ShortForecast
+			}
+			d.TemperatureLow = p.Temperature
+		}
+		if pop := p.ProbabilityOfPrecipitation.float() / 100; !math.IsNaN(pop) && (math.IsNaN(d.PrecipProbability) || pop > d.PrecipProbability) {
+			d.PrecipProbability = pop
+		}
+	}
+
+	for _, d := range days {
+		to := d.Time.AddDate(0, 0, 1)
+		_, d.TemperatureHighTime, _, d.TemperatureLowTime = peak(report.Hourly, d.Time, to, func(p HourlyPoint) float64 {
+			return p.Temperature
+		})
+		d.ApparentTemperatureHigh, d.ApparentTemperatureHighTime, d.ApparentTemperatureLow, d.ApparentTemperatureLowTime = peak(report.Hourly, d.Time, to, func(p HourlyPoint) float64 {
+			return p.ApparentTemperature
+		})
+		d.PrecipAccumulation = math.NaN()
+
+		report.Daily = append(report.Daily, *d)
+	}
+
+	return report
+}
+
+type nws struct {
+	lat       string
+	long      string
+	userAgent string
+
+	url        *url.URL
+	httpClient *http.Client
+	cache      Cache
+}
+
+// NewNWS : Create Forecast instance for US National Weather Service API (api.weather.gov)
+//
+// userAgent は必須 (空の場合は既定値を使う)。
+// cache を指定すると /points で求めた予報の URL を再利用する。
+func NewNWS(lat, long, userAgent string, cache Cache) Forecast {
+	u, err := url.Parse(nwsAPIBase)
+	if err != nil {
+		return nil
+	}
+
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+
+	return &nws{
+		lat:       lat,
+		long:      long,
+		userAgent: userAgent,
+
+		url:        u,
+		httpClient: &http.Client{},
+		cache:      cache,
+	}
+}
+
+// Get : Forecast.Get の実装
+//
+// NWS の予報は英語のみのため lang は無視する
+func (f *nws) Get(lang Lang, units Units, _ ...GetOption) (*Report, error) {
+	grid, err := f.grid(false)
+	if err != nil {
+		return nil, err
+	}
+
+	hourly, daily, err := f.forecasts(grid, units)
+	if e, ok := err.(nwsError); ok && e.Code == http.StatusNotFound {
+		// キャッシュした URL が古くなっている
+		if grid, err = f.grid(true); err != nil {
+			return nil, err
+		}
+		hourly, daily, err = f.forecasts(grid, units)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(grid.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	report := nwsReport(loc, hourly, daily)
+	report.Units = nwsUnits(units)
+
+	return report, nil
+}
+
+func (f *nws) grid(refresh bool) (*nwsGrid, error) {
+	u := *f.url
+	u.Path = path.Join(u.Path, "points", fmt.Sprintf("%s,%s", coordinate(f.lat), coordinate(f.long)))
+
+	key := "nws:" + u.String()
+	if f.cache != nil && !refresh {
+		if b, ok := f.cache.Get(key); ok {
+			grid := nwsGrid{}
+			if err := json.Unmarshal(b, &grid); err == nil {
+				return &grid, nil
+			}
+		}
+	}
+
+	r := struct {
+		Properties nwsGrid `json:"properties"`
+	}{}
+	if err := f.get(u.String(), &r); err != nil {
+		return nil, err
+	}
+
+	if f.cache != nil {
+		if b, err := json.Marshal(r.Properties); err == nil {
+			// キャッシュできなくても予報は返す
+			_ = f.cache.Set(key, b)
+		}
+	}
+
+	return &r.Properties, nil
+}
+
+// nwsUnits : NWS に指定する単位系 (指定がなければ us)
+func nwsUnits(units Units) Units {
+	if units == UnitsUnknown {
+		return UnitsUS
+	}
+
+	return units.system()
+}
+
+func (f *nws) forecasts(grid *nwsGrid, units Units) (*nwsForecast, *nwsForecast, error) {
+	values := url.Values{}
+	values.Set("units", nwsUnits(units).Value())
+
+	hourly := nwsForecast{}
+	if err := f.get(grid.ForecastHourly+"?"+values.Encode(), &hourly); err != nil {
+		return nil, nil, err
+	}
+
+	daily := nwsForecast{}
+	if err := f.get(grid.Forecast+"?"+values.Encode(), &daily); err != nil {
+		return nil, nil, err
+	}
+
+	return &hourly, &daily, nil
+}
+
+func (f *nws) get(u string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "application/geo+json")
+
+	res, err := f.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		e := nwsError{}
+		if err := json.Unmarshal(body, &e); err != nil || e.Title == "" && e.Detail == "" {
+			e.Title = string(body)
+		}
+		e.Code = res.StatusCode
+
+		return e
+	}
+
+	return json.Unmarshal(body, v)
+}
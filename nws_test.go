@@ -0,0 +1,512 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNWSError_Error(t *testing.T) {
+	tests := []struct {
+		err      nwsError
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			err: nwsError{
+				Code:   404,
+				Title:  "Not Found",
+				Detail: "Unable to provide data for requested point 0,0",
+			},
+			expected: "404: Unable to provide data for requested point 0,0",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			err: nwsError{
+				Code:  500,
+				Title: "Unexpected Problem",
+			},
+			expected: "500: Unexpected Problem",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := tt.err.Error()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNWSWeather(t *testing.T) {
+	tests := []struct {
+		icon     string
+		expected Weather
+	}{
+		// TEST0 {{{
+		{
+			icon:     "https://api.weather.gov/icons/land/day/skc?size=small",
+			expected: WeatherClearDay,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			icon:     "https://api.weather.gov/icons/land/night/sct?size=small",
+			expected: WeatherPartlyCloudyNight,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			icon:     "https://api.weather.gov/icons/land/day/rain_showers,60/tsra,60?size=small",
+			expected: WeatherRain,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			icon:     "https://api.weather.gov/icons/land/night/snow_fzra,30?size=small",
+			expected: WeatherSleet,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			icon:     "https://api.weather.gov/icons/land/day/unknown?size=small",
+			expected: WeatherUnknown,
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			icon:     "",
+			expected: WeatherUnknown,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := nwsWeather(tt.icon)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNWSWindSpeed(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected float64
+	}{
+		// TEST0 {{{
+		{
+			s:        "10 mph",
+			expected: 10 * 0.44704,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			s:        "5 to 18 km/h",
+			expected: 18 / 3.6,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			s:        "",
+			expected: math.NaN(),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := nwsWindSpeed(tt.s)
+			if !sameValue(actual, tt.expected) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewNWS(t *testing.T) {
+	lat := "123.45"
+	long := "67.890"
+	cache := &memoryCache{}
+
+	fore := NewNWS(lat, long, "", cache)
+	if fore == nil {
+		t.Fatal("function returns nil")
+	}
+
+	f, ok := fore.(*nws)
+	if !ok {
+		t.Fatal("Expected nws instance, but not.")
+	}
+
+	if f.lat != lat || f.long != long {
+		t.Fatalf("Expected location is %s,%s, but it's %s,%s.", lat, long, f.lat, f.long)
+	}
+
+	if f.userAgent != defaultUserAgent {
+		t.Fatalf("Expected User-Agent is %s, but it's %s.", defaultUserAgent, f.userAgent)
+	}
+
+	if f.url == nil {
+		t.Fatal("url is nil")
+	} else if f.url.String() != nwsAPIBase {
+		t.Fatalf("Expected url is %s, but it's %s.", nwsAPIBase, f.url.String())
+	}
+
+	if f.cache != cache {
+		t.Fatalf("Expected cache is %v, but it's %v.", cache, f.cache)
+	}
+
+	if f.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+}
+
+type nwsServer struct {
+	*httptest.Server
+
+	points    int
+	gridpoint string
+	units     string
+	resStatus int
+	resBody   string
+}
+
+func newNWSServer(units string) *nwsServer {
+	s := &nwsServer{
+		gridpoint: "TOP/32,81",
+		units:     units,
+		resStatus: http.StatusOK,
+	}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+func (s *nwsServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if ua := r.Header.Get("User-Agent"); ua != "test/1.0 test@example.com" {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprintf(w, `{"status":403,"title":"Forbidden","detail":"Unexpected User-Agent: %s"}`, ua)
+		return
+	}
+
+	if s.resStatus != http.StatusOK {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(s.resStatus)
+		w.Write([]byte(s.resBody))
+		return
+	}
+
+	var body string
+	switch r.URL.Path {
+	case "/points/39.7456,-97.0892":
+		s.points++
+		body = strings.Replace(readFile("testdata/nws/points.json"), "{{server}}", s.URL, -1)
+		body = strings.Replace(body, "TOP/32,81", s.gridpoint, -1)
+	case "/gridpoints/" + s.gridpoint + "/forecast/hourly":
+		body = readFile("testdata/nws/hourly.json")
+	case "/gridpoints/" + s.gridpoint + "/forecast":
+		body = readFile("testdata/nws/forecast.json")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"status":404,"title":"Not Found","detail":"Unexpected path: %s"}`, r.URL.Path)
+		return
+	}
+
+	if units := r.URL.Query().Get("units"); !strings.HasPrefix(r.URL.Path, "/points/") && units != s.units {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"status":400,"title":"Bad Request","detail":"Unexpected units: %s"}`, units)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.Write([]byte(body))
+}
+
+func TestNWS_Get(t *testing.T) {
+	chicago := loadLocation("America/Chicago")
+	day0 := time.Date(2018, 1, 31, 0, 0, 0, 0, chicago)
+	day1 := day0.AddDate(0, 0, 1)
+
+	celsius := func(f float64) float64 {
+		return (f - 32) * 5 / 9
+	}
+	apparent := func(f, humidity, mph float64) float64 {
+		return fahrenheit(apparentTemperature(celsius(f), humidity, mph*0.44704))
+	}
+
+	tests := []struct {
+		units Units
+
+		resStatus int
+		resBody   string
+
+		expectedUnits  string
+		expectedHourly []HourlyPoint
+		expectedDaily  []DailyPoint
+		expectedError  error
+	}{
+		// TEST0 {{{
+		{
+			units: UnitsUS,
+
+			resStatus: http.StatusOK,
+
+			expectedUnits: "us",
+			expectedHourly: []HourlyPoint{
+				{
+					Time:                day0,
+					Weather:             WeatherClearNight,
+					Summary:             "Clear",
+					Temperature:         30,
+					ApparentTemperature: apparent(30, 70, 5),
+					PrecipProbability:   0,
+					PrecipAccumulation:  math.NaN(),
+				},
+				{
+					Time:                day0.Add(1 * time.Hour),
+					Weather:             WeatherPartlyCloudyNight,
+					Summary:             "Partly Cloudy",
+					Temperature:         28,
+					ApparentTemperature: apparent(28, 75, 10),
+					PrecipProbability:   0.1,
+					PrecipAccumulation:  math.NaN(),
+				},
+				{
+					Time:                day0.Add(12 * time.Hour),
+					Weather:             WeatherPartlyCloudyDay,
+					Summary:             "Mostly Cloudy",
+					Temperature:         45,
+					ApparentTemperature: apparent(45, 50, 10),
+					PrecipProbability:   0.2,
+					PrecipAccumulation:  math.NaN(),
+				},
+				{
+					Time:                day0.Add(15 * time.Hour),
+					Weather:             WeatherRain,
+					Summary:             "Rain Showers Likely",
+					Temperature:         48,
+					ApparentTemperature: apparent(48, 60, 15),
+					PrecipProbability:   0.6,
+					PrecipAccumulation:  math.NaN(),
+				},
+				{
+					Time:                day1,
+					Weather:             WeatherSnow,
+					Summary:             "Light Snow",
+					Temperature:         35,
+					ApparentTemperature: apparent(35, 80, 10),
+					PrecipProbability:   math.NaN(),
+					PrecipAccumulation:  math.NaN(),
+				},
+			},
+			expectedDaily: []DailyPoint{
+				{
+					Time:                        day0,
+					Weather:                     WeatherRain,
+					Summary:                     "Rain Showers Likely",
+					TemperatureHigh:             50,
+					TemperatureHighTime:         day0.Add(15 * time.Hour),
+					TemperatureLow:              33,
+					TemperatureLowTime:          day0.Add(1 * time.Hour),
+					ApparentTemperatureHigh:     apparent(48, 60, 15),
+					ApparentTemperatureHighTime: day0.Add(15 * time.Hour),
+					ApparentTemperatureLow:      apparent(28, 75, 10),
+					ApparentTemperatureLowTime:  day0.Add(1 * time.Hour),
+					PrecipProbability:           0.7,
+					PrecipAccumulation:          math.NaN(),
+				},
+				{
+					Time:                        day1,
+					Weather:                     WeatherClearDay,
+					Summary:                     "Sunny",
+					TemperatureHigh:             40,
+					TemperatureHighTime:         day1,
+					TemperatureLow:              20,
+					TemperatureLowTime:          day1,
+					ApparentTemperatureHigh:     apparent(35, 80, 10),
+					ApparentTemperatureHighTime: day1,
+					ApparentTemperatureLow:      apparent(35, 80, 10),
+					ApparentTemperatureLowTime:  day1,
+					PrecipProbability:           math.NaN(),
+					PrecipAccumulation:          math.NaN(),
+				},
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			units: UnitsSI,
+
+			resStatus: http.StatusOK,
+
+			expectedUnits: "si",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			units: UnitsSI,
+
+			resStatus: http.StatusNotFound,
+			resBody:   `{"type":"https://api.weather.gov/problems/InvalidPoint","title":"Data Unavailable For Requested Point","status":404,"detail":"Unable to provide data for requested point 39.7456,-97.0892"}`,
+
+			expectedUnits: "si",
+			expectedError: nwsError{
+				Code:   http.StatusNotFound,
+				Detail: "Unable to provide data for requested point 39.7456,-97.0892",
+			},
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			units: UnitsSI,
+
+			resStatus: http.StatusBadGateway,
+			resBody:   "Bad Gateway",
+
+			expectedUnits: "si",
+			expectedError: nwsError{
+				Code:  http.StatusBadGateway,
+				Title: "Bad Gateway",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			server := newNWSServer(tt.expectedUnits)
+			defer server.Close()
+			server.resStatus = tt.resStatus
+			server.resBody = tt.resBody
+
+			var err error
+			f := &nws{
+				lat:       "39.745612",
+				long:      "-97.08921",
+				userAgent: "test/1.0 test@example.com",
+			}
+			f.url, err = neturl.Parse(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.httpClient = server.Client()
+
+			res, err := f.Get(LangEn, tt.units)
+			if err != nil {
+				if tt.expectedError == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err.Error() != tt.expectedError.Error() {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expectedError, err)
+				}
+				return
+			}
+
+			if tt.expectedError != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+				return
+			}
+
+			if res.Location.String() != chicago.String() {
+				t.Errorf("Expected to get [%v], but got [%v]", chicago, res.Location)
+			}
+			if tt.expectedHourly != nil && !sameValue(res.Hourly, tt.expectedHourly) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expectedHourly, res.Hourly)
+			}
+			if tt.expectedDaily != nil && !sameValue(res.Daily, tt.expectedDaily) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expectedDaily, res.Daily)
+			}
+		})
+	}
+}
+
+func TestNWS_Get_cache(t *testing.T) {
+	server := newNWSServer("si")
+	defer server.Close()
+
+	var err error
+	f := &nws{
+		lat:       "39.7456",
+		long:      "-97.0892",
+		userAgent: "test/1.0 test@example.com",
+		cache:     &memoryCache{},
+	}
+	f.url, err = neturl.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.httpClient = server.Client()
+
+	tests := []struct {
+		gridpoint string
+
+		expectedPoints int
+	}{
+		// TEST0 {{{
+		{
+			gridpoint: "TOP/32,81",
+
+			expectedPoints: 1,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			gridpoint: "TOP/32,81",
+
+			expectedPoints: 1, // キャッシュした URL を使う
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			gridpoint: "TOP/31,80",
+
+			expectedPoints: 2, // 404 になったら求め直す
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		server.gridpoint = tt.gridpoint
+
+		res, err := f.Get(LangEn, UnitsSI)
+		if err != nil {
+			t.Fatalf("%02d: Expected no error occurred, but it occurred (%v)", i, err)
+		}
+
+		if server.points != tt.expectedPoints {
+			t.Errorf("%02d: Expected %d requests, but got %d", i, tt.expectedPoints, server.points)
+		}
+
+		if len(res.Hourly) != 5 {
+			t.Errorf("%02d: Expected %d hours, but got %d", i, 5, len(res.Hourly))
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	openMeteoAPIBase = "https://api.open-meteo.com"
+)
+
+var (
+	openMeteoHourly = []string{
+		"temperature_2m",
+		"apparent_temperature",
+		"precipitation_probability",
+		"snowfall",
+		"weather_code",
+		"is_day",
+	}
+
+	openMeteoDaily = []string{
+		"weather_code",
+		"temperature_2m_max",
+		"temperature_2m_min",
+		"apparent_temperature_max",
+		"apparent_temperature_min",
+		"precipitation_probability_max",
+		"snowfall_sum",
+	}
+)
+
+type openMeteoError struct {
+	Code   int    `json:"-"`
+	Reason string `json:"reason"`
+}
+
+func (e openMeteoError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Reason)
+}
+
+type wmoCode struct {
+	weather   Weather
+	summaries map[Lang]string
+}
+
+// WMO weather interpretation codes
+var wmoCodes = map[int]wmoCode{
+	0:  {WeatherClearDay, map[Lang]string{LangEn: "Clear sky", LangJa: "快晴"}},
+	1:  {WeatherClearDay, map[Lang]string{LangEn: "Mainly clear", LangJa: "晴れ"}},
+	2:  {WeatherPartlyCloudyDay, map[Lang]string{LangEn: "Partly cloudy", LangJa: "晴れ時々曇り"}},
+	3:  {WeatherCloudy, map[Lang]string{LangEn: "Overcast", LangJa: "曇り"}},
+	45: {WeatherFog, map[Lang]string{LangEn: "Fog", LangJa: "霧"}},
+	48: {WeatherFog, map[Lang]string{LangEn: "Depositing rime fog", LangJa: "着氷性の霧"}},
+	51: {WeatherRain, map[Lang]string{LangEn: "Light drizzle", LangJa: "弱い霧雨"}},
+	53: {WeatherRain, map[Lang]string{LangEn: "Moderate drizzle", LangJa: "霧雨"}},
+	55: {WeatherRain, map[Lang]string{LangEn: "Dense drizzle", LangJa: "強い霧雨"}},
+	56: {WeatherSleet, map[Lang]string{LangEn: "Light freezing drizzle", LangJa: "弱い着氷性の霧雨"}},
+	57: {WeatherSleet, map[Lang]string{LangEn: "Dense freezing drizzle", LangJa: "強い着氷性の霧雨"}},
+	61: {WeatherRain, map[Lang]string{LangEn: "Slight rain", LangJa: "小雨"}},
+	63: {WeatherRain, map[Lang]string{LangEn: "Moderate rain", LangJa: "雨"}},
+	65: {WeatherRain, map[Lang]string{LangEn: "Heavy rain", LangJa: "大雨"}},
+	66: {WeatherSleet, map[Lang]string{LangEn: "Light freezing rain", LangJa: "弱い着氷性の雨"}},
+	67: {WeatherSleet, map[Lang]string{LangEn: "Heavy freezing rain", LangJa: "強い着氷性の雨"}},
+	71: {WeatherSnow, map[Lang]string{LangEn: "Slight snow fall", LangJa: "小雪"}},
+	73: {WeatherSnow, map[Lang]string{LangEn: "Moderate snow fall", LangJa: "雪"}},
+	75: {WeatherSnow, map[Lang]string{LangEn: "Heavy snow fall", LangJa: "大雪"}},
+	77: {WeatherSnow, map[Lang]string{LangEn: "Snow grains", LangJa: "霧雪"}},
+	80: {WeatherRain, map[Lang]string{LangEn: "Slight rain showers", LangJa: "弱いにわか雨"}},
+	81: {WeatherRain, map[Lang]string{LangEn: "Moderate rain showers", LangJa: "にわか雨"}},
+	82: {WeatherRain, map[Lang]string{LangEn: "Violent rain showers", LangJa: "激しいにわか雨"}},
+	85: {WeatherSnow, map[Lang]string{LangEn: "Slight snow showers", LangJa: "弱いにわか雪"}},
+	86: {WeatherSnow, map[Lang]string{LangEn: "Heavy snow showers", LangJa: "強いにわか雪"}},
+	95: {WeatherRain, map[Lang]string{LangEn: "Thunderstorm", LangJa: "雷雨"}},
+	96: {WeatherRain, map[Lang]string{LangEn: "Thunderstorm with slight hail", LangJa: "雷雨 (弱いひょう)"}},
+	99: {WeatherRain, map[Lang]string{LangEn: "Thunderstorm with heavy hail", LangJa: "雷雨 (強いひょう)"}},
+}
+
+// wmoWeather : WMO コードを天気種別と概要に変換する
+func wmoWeather(code *int, day bool, lang Lang) (Weather, string) {
+	if code == nil {
+		return WeatherUnknown, ""
+	}
+
+	c, ok := wmoCodes[*code]
+	if !ok {
+		return WeatherUnknown, ""
+	}
+
+	summary, ok := c.summaries[lang]
+	if !ok {
+		summary = c.summaries[LangEn]
+	}
+
+	if !day {
+		return c.weather.night(), summary
+	}
+
+	return c.weather, summary
+}
+
+type openMeteoResponse struct {
+	TimeZone TimeZone `json:"timezone"`
+	Hourly   struct {
+		Time                []int64    `json:"time"`
+		Temperature         []*float64 `json:"temperature_2m"`
+		ApparentTemperature []*float64 `json:"apparent_temperature"`
+		PrecipProbability   []*float64 `json:"precipitation_probability"` // %
+		Snowfall            []*float64 `json:"snowfall"`
+		WeatherCode         []*int     `json:"weather_code"`
+		IsDay               []*int     `json:"is_day"`
+	} `json:"hourly"`
+	Daily struct {
+		Time                   []int64    `json:"time"`
+		WeatherCode            []*int     `json:"weather_code"`
+		TemperatureMax         []*float64 `json:"temperature_2m_max"`
+		TemperatureMin         []*float64 `json:"temperature_2m_min"`
+		ApparentTemperatureMax []*float64 `json:"apparent_temperature_max"`
+		ApparentTemperatureMin []*float64 `json:"apparent_temperature_min"`
+		PrecipProbabilityMax   []*float64 `json:"precipitation_probability_max"` // %
+		SnowfallSum            []*float64 `json:"snowfall_sum"`
+	} `json:"daily"`
+}
+
+// valueAt : null や欠損は NaN として値を返す
+func valueAt(values []*float64, i int) float64 {
+	if i >= len(values) || values[i] == nil {
+		return math.NaN()
+	}
+
+	return *values[i]
+}
+
+func codeAt(values []*int, i int) *int {
+	if i >= len(values) {
+		return nil
+	}
+
+	return values[i]
+}
+
+func (r *openMeteoResponse) report(lang Lang, units Units) *Report {
+	loc := r.TimeZone.Location()
+
+	report := &Report{
+		Location: loc,
+		Units:    units.system(),
+	}
+	for i, t := range r.Hourly.Time {
+		day := true
+		if isDay := codeAt(r.Hourly.IsDay, i); isDay != nil && *isDay == 0 {
+			day = false
+		}
+		weather, summary := wmoWeather(codeAt(r.Hourly.WeatherCode, i), day, lang)
+
+		report.Hourly = append(report.Hourly, HourlyPoint{
+			Time:                time.Unix(t, 0).In(loc),
+			Weather:             weather,
+			Summary:             summary,
+			Temperature:         valueAt(r.Hourly.Temperature, i),
+			ApparentTemperature: valueAt(r.Hourly.ApparentTemperature, i),
+			PrecipProbability:   valueAt(r.Hourly.PrecipProbability, i) / 100,
+			PrecipAccumulation:  valueAt(r.Hourly.Snowfall, i),
+		})
+	}
+	for i, t := range r.Daily.Time {
+		from := time.Unix(t, 0).In(loc)
+		to := from.AddDate(0, 0, 1)
+		_, highTime, _, lowTime := peak(report.Hourly, from, to, func(p HourlyPoint) float64 {
+			return p.Temperature
+		})
+		_, apparentHighTime, _, apparentLowTime := peak(report.Hourly, from, to, func(p HourlyPoint) float64 {
+			return p.ApparentTemperature
+		})
+		weather, summary := wmoWeather(codeAt(r.Daily.WeatherCode, i), true, lang)
+
+		report.Daily = append(report.Daily, DailyPoint{
+			Time:                        from,
+			Weather:                     weather,
+			Summary:                     summary,
+			TemperatureHigh:             valueAt(r.Daily.TemperatureMax, i),
+			TemperatureHighTime:         highTime,
+			TemperatureLow:              valueAt(r.Daily.TemperatureMin, i),
+			TemperatureLowTime:          lowTime,
+			ApparentTemperatureHigh:     valueAt(r.Daily.ApparentTemperatureMax, i),
+			ApparentTemperatureHighTime: apparentHighTime,
+			ApparentTemperatureLow:      valueAt(r.Daily.ApparentTemperatureMin, i),
+			ApparentTemperatureLowTime:  apparentLowTime,
+			PrecipProbability:           valueAt(r.Daily.PrecipProbabilityMax, i) / 100,
+			PrecipAccumulation:          valueAt(r.Daily.SnowfallSum, i),
+		})
+	}
+
+	return report
+}
+
+type openMeteo struct {
+	lat  string
+	long string
+
+	url        *url.URL
+	httpClient *http.Client
+}
+
+// NewOpenMeteo : Create Forecast instance for Open-Meteo API
+func NewOpenMeteo(lat, long string) Forecast {
+	u, err := url.Parse(openMeteoAPIBase)
+	if err != nil {
+		return nil
+	}
+
+	u.Path = path.Join(u.Path, "v1", "forecast")
+	return &openMeteo{
+		lat:  lat,
+		long: long,
+
+		url:        u,
+		httpClient: &http.Client{},
+	}
+}
+
+// Get : Forecast.Get の実装
+func (f *openMeteo) Get(lang Lang, units Units, _ ...GetOption) (*Report, error) {
+	values := url.Values{}
+	values.Set("latitude", f.lat)
+	values.Set("longitude", f.long)
+	values.Set("hourly", strings.Join(openMeteoHourly, ","))
+	values.Set("daily", strings.Join(openMeteoDaily, ","))
+	values.Set("timezone", "auto")
+	values.Set("timeformat", "unixtime")
+	if units == UnitsUS {
+		values.Set("temperature_unit", "fahrenheit")
+		values.Set("precipitation_unit", "inch")
+	}
+
+	u := *f.url
+	u.RawQuery = values.Encode()
+
+	res, err := f.httpClient.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		e := openMeteoError{}
+		if err := json.Unmarshal(body, &e); err != nil || e.Reason == "" {
+			e.Reason = string(body)
+		}
+		e.Code = res.StatusCode
+
+		return nil, e
+	}
+
+	r := openMeteoResponse{}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+
+	return r.report(lang, units), nil
+}
@@ -0,0 +1,416 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenMeteoError_Error(t *testing.T) {
+	err := openMeteoError{
+		Code:   400,
+		Reason: "This is test",
+	}
+
+	expected := "400: This is test"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestWMOWeather(t *testing.T) {
+	code := func(c int) *int {
+		return &c
+	}
+
+	tests := []struct {
+		code *int
+		day  bool
+		lang Lang
+
+		expectedWeather Weather
+		expectedSummary string
+	}{
+		// TEST0 {{{
+		{
+			code: code(0),
+			day:  true,
+			lang: LangEn,
+
+			expectedWeather: WeatherClearDay,
+			expectedSummary: "Clear sky",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			code: code(2),
+			day:  false,
+			lang: LangJa,
+
+			expectedWeather: WeatherPartlyCloudyNight,
+			expectedSummary: "晴れ時々曇り",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			code: code(66),
+			day:  true,
+			lang: LangUnknown,
+
+			expectedWeather: WeatherSleet,
+			expectedSummary: "Light freezing rain",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			code: code(86),
+			day:  false,
+			lang: LangJa,
+
+			expectedWeather: WeatherSnow,
+			expectedSummary: "強いにわか雪",
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			code: code(4),
+			day:  true,
+			lang: LangEn,
+
+			expectedWeather: WeatherUnknown,
+			expectedSummary: "",
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			code: nil,
+			day:  true,
+			lang: LangEn,
+
+			expectedWeather: WeatherUnknown,
+			expectedSummary: "",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			weather, summary := wmoWeather(tt.code, tt.day, tt.lang)
+			if weather != tt.expectedWeather {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expectedWeather, weather)
+			}
+			if summary != tt.expectedSummary {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expectedSummary, summary)
+			}
+		})
+	}
+}
+
+func TestNewOpenMeteo(t *testing.T) {
+	lat := "123.45"
+	long := "67.890"
+
+	fore := NewOpenMeteo(lat, long)
+
+	if fore == nil {
+		t.Fatal("function returns nil")
+	}
+
+	f, ok := fore.(*openMeteo)
+	if !ok {
+		t.Fatal("Expected openMeteo instance, but not.")
+	}
+
+	if f.lat != lat || f.long != long {
+		t.Fatalf("Expected location is %s,%s, but it's %s,%s.", lat, long, f.lat, f.long)
+	}
+
+	expectedURL := fmt.Sprintf("%s/v1/forecast", openMeteoAPIBase)
+	if f.url == nil {
+		t.Fatal("url is nil")
+	} else if f.url.String() != expectedURL {
+		t.Fatalf("Expected url is %s, but it's %s.", expectedURL, f.url.String())
+	}
+
+	if f.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+}
+
+func writeOpenMeteoErrorResponse(w http.ResponseWriter, status int, reason string) {
+	w.WriteHeader(status)
+	if _, err := fmt.Fprintf(w, `{"error":true,"reason":"%s"}`, reason); err != nil {
+		panic(err)
+	}
+}
+
+func openMeteoFunc(units Units, resStatus int, response string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeOpenMeteoErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Unexpected request: method = %s", r.Method))
+			return
+		}
+
+		if err := checkOpenMeteoQuery(units, r.URL.Query()); err != nil {
+			writeOpenMeteoErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		w.WriteHeader(resStatus)
+		w.Write([]byte(response))
+	}
+}
+
+func checkOpenMeteoQuery(units Units, query neturl.Values) error {
+	expected := map[string]string{
+		"latitude":   "34.9208",
+		"longitude":  "136.9886",
+		"hourly":     strings.Join(openMeteoHourly, ","),
+		"daily":      strings.Join(openMeteoDaily, ","),
+		"timezone":   "auto",
+		"timeformat": "unixtime",
+	}
+	if units == UnitsUS {
+		expected["temperature_unit"] = "fahrenheit"
+		expected["precipitation_unit"] = "inch"
+	}
+
+	for k, v := range expected {
+		if val := query.Get(k); val != v {
+			return fmt.Errorf("Unexpected request: `%s` in query = %s", k, val)
+		}
+	}
+	for _, k := range []string{"temperature_unit", "precipitation_unit"} {
+		if _, ok := expected[k]; !ok && query.Get(k) != "" {
+			return fmt.Errorf("Unexpected request: `%s` in query = %s", k, query.Get(k))
+		}
+	}
+
+	return nil
+}
+
+func TestOpenMeteo_Get(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+	newYork := loadLocation("America/New_York")
+	day0 := time.Unix(1517324400, 0).In(tokyo)
+	day1 := day0.AddDate(0, 0, 1)
+
+	tests := []struct {
+		lang  Lang
+		units Units
+
+		resStatus  int
+		resMessage string
+
+		expectedLocation *time.Location
+		expectedHourly   map[int]HourlyPoint
+		expectedHours    int
+		expectedDaily    []DailyPoint
+		expectedError    error
+	}{
+		// TEST0 {{{
+		{
+			lang:  LangJa,
+			units: UnitsSI,
+
+			resStatus:  http.StatusOK,
+			resMessage: readFile("testdata/openmeteo/get00.json"),
+
+			expectedLocation: tokyo,
+			expectedHourly: map[int]HourlyPoint{
+				0: {
+					Time:                day0,
+					Weather:             WeatherClearNight,
+					Summary:             "快晴",
+					Temperature:         0.1,
+					ApparentTemperature: -3.1,
+					PrecipProbability:   0,
+					PrecipAccumulation:  0,
+				},
+				14: {
+					Time:                day0.Add(14 * time.Hour),
+					Weather:             WeatherPartlyCloudyDay,
+					Summary:             "晴れ時々曇り",
+					Temperature:         8.5,
+					ApparentTemperature: 5.3,
+					PrecipProbability:   0,
+					PrecipAccumulation:  0,
+				},
+				28: {
+					Time:                day1.Add(4 * time.Hour),
+					Weather:             WeatherSnow,
+					Summary:             "小雪",
+					Temperature:         -2.9,
+					ApparentTemperature: -6.1,
+					PrecipProbability:   0.8,
+					PrecipAccumulation:  0.3,
+				},
+				47: {
+					Time:                day1.Add(23 * time.Hour),
+					Weather:             WeatherClearNight,
+					Summary:             "快晴",
+					Temperature:         -2.2,
+					ApparentTemperature: -5.4,
+					PrecipProbability:   math.NaN(),
+					PrecipAccumulation:  0,
+				},
+			},
+			expectedHours: 48,
+			expectedDaily: []DailyPoint{
+				{
+					Time:                        day0,
+					Weather:                     WeatherPartlyCloudyDay,
+					Summary:                     "晴れ時々曇り",
+					TemperatureHigh:             8.5,
+					TemperatureHighTime:         day0.Add(14 * time.Hour),
+					TemperatureLow:              -0.5,
+					TemperatureLowTime:          day0.Add(2 * time.Hour),
+					ApparentTemperatureHigh:     5.3,
+					ApparentTemperatureHighTime: day0.Add(14 * time.Hour),
+					ApparentTemperatureLow:      -3.7,
+					ApparentTemperatureLowTime:  day0.Add(2 * time.Hour),
+					PrecipProbability:           0.15,
+					PrecipAccumulation:          0,
+				},
+				{
+					Time:                        day1,
+					Weather:                     WeatherSnow,
+					Summary:                     "雪",
+					TemperatureHigh:             5.5,
+					TemperatureHighTime:         day1.Add(14 * time.Hour),
+					TemperatureLow:              -3.5,
+					TemperatureLowTime:          day1.Add(2 * time.Hour),
+					ApparentTemperatureHigh:     2.3,
+					ApparentTemperatureHighTime: day1.Add(14 * time.Hour),
+					ApparentTemperatureLow:      -6.7,
+					ApparentTemperatureLowTime:  day1.Add(2 * time.Hour),
+					PrecipProbability:           0.8,
+					PrecipAccumulation:          1.77,
+				},
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			lang:  LangEn,
+			units: UnitsUS,
+
+			resStatus:  http.StatusOK,
+			resMessage: readFile("testdata/openmeteo/get01.json"),
+
+			expectedLocation: newYork,
+			expectedHours:    0,
+			expectedDaily: []DailyPoint{
+				{
+					Time:                    time.Unix(1517374800, 0).In(newYork),
+					Weather:                 WeatherRain,
+					Summary:                 "Thunderstorm",
+					TemperatureHigh:         41.2,
+					TemperatureLow:          30.1,
+					ApparentTemperatureHigh: 36.5,
+					ApparentTemperatureLow:  22.4,
+					PrecipProbability:       0.65,
+					PrecipAccumulation:      0,
+				},
+				{
+					Time:                    time.Unix(1517461200, 0).In(newYork),
+					Weather:                 WeatherSnow,
+					Summary:                 "Slight snow showers",
+					TemperatureHigh:         35.6,
+					TemperatureLow:          27.3,
+					ApparentTemperatureHigh: 29.8,
+					ApparentTemperatureLow:  19,
+					PrecipProbability:       math.NaN(),
+					PrecipAccumulation:      0.83,
+				},
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			lang:  LangJa,
+			units: UnitsSI,
+
+			resStatus:  http.StatusBadRequest,
+			resMessage: `{"error":true,"reason":"Latitude must be in range of -90 to 90°. Given: 999.0."}`,
+
+			expectedError: openMeteoError{
+				Code:   http.StatusBadRequest,
+				Reason: "Latitude must be in range of -90 to 90°. Given: 999.0.",
+			},
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			lang:  LangJa,
+			units: UnitsSI,
+
+			resStatus:  http.StatusBadGateway,
+			resMessage: "Bad Gateway",
+
+			expectedError: openMeteoError{
+				Code:   http.StatusBadGateway,
+				Reason: "Bad Gateway",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(openMeteoFunc(tt.units, tt.resStatus, tt.resMessage)))
+			defer server.Close()
+
+			var err error
+			f := &openMeteo{lat: "34.9208", long: "136.9886"}
+			f.url, err = neturl.Parse(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.httpClient = server.Client()
+
+			res, err := f.Get(tt.lang, tt.units)
+			if err != nil {
+				if tt.expectedError == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err.Error() != tt.expectedError.Error() {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expectedError, err)
+				}
+				return
+			}
+
+			if tt.expectedError != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+				return
+			}
+
+			if res.Location.String() != tt.expectedLocation.String() {
+				t.Errorf("Expected location is %s, but it's %s", tt.expectedLocation, res.Location)
+			}
+			if len(res.Hourly) != tt.expectedHours {
+				t.Fatalf("Expected %d hours, but got %d", tt.expectedHours, len(res.Hourly))
+			}
+			for i, expected := range tt.expectedHourly {
+				if !sameValue(res.Hourly[i], expected) {
+					t.Errorf("Expected to get [%+v], but got [%+v]", expected, res.Hourly[i])
+				}
+			}
+			if !sameValue(res.Daily, tt.expectedDaily) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expectedDaily, res.Daily)
+			}
+		})
+	}
+}
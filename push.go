@@ -0,0 +1,54 @@
+package weatherline
+
+import (
+	"math"
+)
+
+const (
+	// DefaultPushThreshold : 通知の優先度を上げる降水確率のデフォルト値
+	DefaultPushThreshold = 0.5
+)
+
+// pushLevel : 予報から決める通知の重要度
+type pushLevel int
+
+const (
+	pushLevelDefault pushLevel = iota
+	pushLevelHigh
+)
+
+// pushAlert : 対象日の重要度と最も多い天気
+//
+// 降水確率が threshold 以上の時間がある場合か、雪が降る場合、気象警報がある場合は重要度を上げる。
+// 時間別予報がなければ翌日の日別予報で判断する。
+func pushAlert(msg *Message, threshold float64) (pushLevel, Weather) {
+	hourly := msg.HourlyPoints()
+
+	weathers := []Weather{}
+	for _, p := range hourly {
+		weathers = append(weathers, p.Weather)
+	}
+	probability := maximum(len(hourly), func(i int) float64 { return hourly[i].PrecipProbability })
+
+	if len(weathers) == 0 {
+		if daily := msg.DailyPoints(); len(daily) > 0 {
+			weathers = append(weathers, daily[0].Weather)
+			probability = daily[0].PrecipProbability
+		}
+	}
+
+	level := pushLevelDefault
+	for _, w := range weathers {
+		if w == WeatherSnow {
+			level = pushLevelHigh
+		}
+	}
+	if !math.IsNaN(probability) && probability >= threshold {
+		level = pushLevelHigh
+	}
+	if len(msg.Alerts) > 0 {
+		level = pushLevelHigh
+	}
+
+	return level, dominantWeather(weathers)
+}
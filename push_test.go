@@ -0,0 +1,166 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPushAlert(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+	date := time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo)
+
+	hourly := func(h int, w Weather, pop float64) HourlyPoint {
+		return HourlyPoint{
+			Time:              date.Add(time.Duration(h) * time.Hour),
+			Weather:           w,
+			PrecipProbability: pop,
+		}
+	}
+	daily := func(d int, w Weather, pop float64) DailyPoint {
+		return DailyPoint{
+			Time:              date.AddDate(0, 0, d),
+			Weather:           w,
+			PrecipProbability: pop,
+		}
+	}
+
+	tests := []struct {
+		report    *Report
+		alerts    []Alert
+		threshold float64
+
+		expectedLevel   pushLevel
+		expectedWeather Weather
+	}{
+		// TEST0 {{{
+		{
+			report: &Report{
+				Hourly: []HourlyPoint{
+					hourly(9, WeatherClearDay, 0.1),
+					hourly(12, WeatherClearDay, 0.2),
+					hourly(15, WeatherCloudy, 0.4),
+				},
+			},
+			threshold: 0.5,
+
+			expectedLevel:   pushLevelDefault,
+			expectedWeather: WeatherClearDay,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			report: &Report{
+				Hourly: []HourlyPoint{
+					hourly(9, WeatherCloudy, 0.3),
+					hourly(12, WeatherRain, 0.5),
+					hourly(15, WeatherCloudy, math.NaN()),
+				},
+			},
+			threshold: 0.5,
+
+			expectedLevel:   pushLevelHigh,
+			expectedWeather: WeatherCloudy,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			report: &Report{
+				Hourly: []HourlyPoint{
+					hourly(9, WeatherCloudy, 0.1),
+					hourly(12, WeatherSnow, 0.2),
+					hourly(15, WeatherCloudy, 0.1),
+				},
+			},
+			threshold: 0.5,
+
+			expectedLevel:   pushLevelHigh,
+			expectedWeather: WeatherCloudy,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			report: &Report{
+				Hourly: []HourlyPoint{
+					hourly(9, WeatherCloudy, 0.3),
+				},
+				Daily: []DailyPoint{
+					daily(1, WeatherRain, 0.9),
+				},
+			},
+			threshold: 0.2,
+
+			expectedLevel:   pushLevelHigh,
+			expectedWeather: WeatherCloudy,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			report: &Report{
+				Daily: []DailyPoint{
+					daily(1, WeatherRain, 0.9),
+					daily(2, WeatherSnow, 0.9),
+				},
+			},
+			threshold: 0.5,
+
+			expectedLevel:   pushLevelHigh,
+			expectedWeather: WeatherRain,
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			report: &Report{
+				Daily: []DailyPoint{
+					daily(1, WeatherCloudy, math.NaN()),
+				},
+			},
+			threshold: 0.5,
+
+			expectedLevel:   pushLevelDefault,
+			expectedWeather: WeatherCloudy,
+		},
+		// }}}
+		// TEST6 {{{
+		{
+			report:    nil,
+			threshold: 0.5,
+
+			expectedLevel:   pushLevelDefault,
+			expectedWeather: WeatherUnknown,
+		},
+		// }}}
+		// TEST7 {{{
+		{
+			report: &Report{
+				Hourly: []HourlyPoint{
+					hourly(9, WeatherClearDay, 0.1),
+				},
+			},
+			alerts:    []Alert{{Title: "Typhoon Warning", Severity: "warning"}},
+			threshold: 0.5,
+
+			expectedLevel:   pushLevelHigh,
+			expectedWeather: WeatherClearDay,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			msg := &Message{Date: date, Days: 3, Report: tt.report, Alerts: tt.alerts}
+
+			level, weather := pushAlert(msg, tt.threshold)
+			if level != tt.expectedLevel {
+				t.Errorf("Expected level is %d, but got %d", tt.expectedLevel, level)
+			}
+			if weather != tt.expectedWeather {
+				t.Errorf("Expected weather is %d, but got %d", tt.expectedWeather, weather)
+			}
+		})
+	}
+}
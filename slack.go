@@ -0,0 +1,194 @@
+package weatherline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// 1メッセージに含められるブロックの最大数
+	slackBlocksMax = 50
+)
+
+type slackError struct {
+	Code    int
+	Message string
+}
+
+func (e slackError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackPayload struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks,omitempty"`
+}
+
+type slack struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewSlack : Create Notifier instance for Slack (Incoming Webhooks)
+func NewSlack(webhookURL string) Notifier {
+	return &slack{
+		url:        webhookURL,
+		httpClient: &http.Client{},
+	}
+}
+
+// slackEscape : mrkdwn の制御文字をエスケープする
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func mrkdwn(format string, a ...interface{}) slackText {
+	return slackText{
+		Type: "mrkdwn",
+		Text: fmt.Sprintf(format, a...),
+	}
+}
+
+// blocks : Block Kit のレイアウト
+//
+// 気象警報の section、日付のヘッダー、1時間ごとの fields、1日ごとの context からなる
+func (n *slack) blocks(msg *Message) []slackBlock {
+	hourly := msg.HourlyPoints()
+	daily := msg.DailyPoints()
+	if len(msg.Alerts) == 0 && len(hourly) == 0 && len(daily) == 0 {
+		return nil
+	}
+
+	f := msg.Format()
+	blocks := []slackBlock{}
+
+	if len(msg.Alerts) > 0 {
+		lines := []string{}
+		for _, a := range msg.Alerts {
+			lines = append(lines, fmt.Sprintf("*%s*", slackEscape(AlertText(a))))
+		}
+
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{
+				Type: "mrkdwn",
+				Text: strings.Join(lines, "\n"),
+			},
+		})
+	}
+
+	if len(hourly) > 0 || len(daily) > 0 {
+		blocks = append(blocks, slackBlock{
+			Type: "header",
+			Text: &slackText{
+				Type:  "plain_text",
+				Text:  msg.Date.Format("01/02 (Mon)"),
+				Emoji: true,
+			},
+		})
+	}
+
+	for _, p := range hourly {
+		weather := slackEscape(WeatherText(p.Weather, p.Summary))
+
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Fields: []slackText{
+				mrkdwn("*%s* %s", p.Time.Format("15:04"), weather),
+				mrkdwn("%s/%s %s",
+					f.Temperature(p.Temperature),
+					f.Temperature(p.ApparentTemperature),
+					f.Precip(p.Weather, p.PrecipProbability, p.PrecipAccumulation)),
+			},
+		})
+	}
+
+	if len(daily) > 0 {
+		blocks = append(blocks, slackBlock{Type: "divider"})
+	}
+	for _, p := range daily {
+		blocks = append(blocks, slackBlock{
+			Type: "context",
+			Elements: []slackText{
+				mrkdwn("*%s* %s %s", p.Time.Format("01/02"), IconText(p.Weather), f.Precip(p.Weather, p.PrecipProbability, p.PrecipAccumulation)),
+				mrkdwn("▲ %s/%s (%s)",
+					f.Temperature(p.TemperatureHigh),
+					f.Temperature(p.ApparentTemperatureHigh),
+					FormatTime("15:04", p.ApparentTemperatureHighTime)),
+				mrkdwn("▼ %s/%s (%s)",
+					f.Temperature(p.TemperatureLow),
+					f.Temperature(p.ApparentTemperatureLow),
+					FormatTime("15:04", p.ApparentTemperatureLowTime)),
+			},
+		})
+	}
+
+	if len(blocks) > slackBlocksMax {
+		blocks = blocks[:slackBlocksMax]
+	}
+
+	return blocks
+}
+
+// payload : リクエストボディ
+//
+// text は通知やブロックを表示できないクライアント向け
+func (n *slack) payload(msg *Message) ([]byte, error) {
+	return json.Marshal(slackPayload{
+		Text:   slackEscape(strings.TrimSpace(msg.Text)),
+		Blocks: n.blocks(msg),
+	})
+}
+
+// Notify : Notifier.Notify の実装
+func (n *slack) Notify(msg *Message) error {
+	body, err := n.payload(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	// Incoming Webhooks のエラーは "invalid_payload" などのテキストで返る
+	return slackError{
+		Code:    res.StatusCode,
+		Message: strings.TrimSpace(string(b)),
+	}
+}
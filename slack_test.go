@@ -0,0 +1,230 @@
+package weatherline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSlackError_Error(t *testing.T) {
+	err := slackError{
+		Code:    404,
+		Message: "no_service",
+	}
+
+	expected := "404: no_service"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestNewSlack(t *testing.T) {
+	webhookURL := "https://hooks.slack.com/services/T000/B000/XXXX"
+
+	notifier := NewSlack(webhookURL)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*slack)
+	if !ok {
+		t.Fatal("Expected slack instance, but not.")
+	}
+
+	if n.url != webhookURL {
+		t.Fatalf("Expected url is %s, but it's %s.", webhookURL, n.url)
+	}
+
+	if n.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+}
+
+func TestSlackEscape(t *testing.T) {
+	s := "<雨> & 雪"
+	expected := "&lt;雨&gt; &amp; 雪"
+
+	actual := slackEscape(s)
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func indentJSON(b []byte) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, "", "  "); err != nil {
+		panic(err)
+	}
+	buf.WriteString("\n")
+
+	return buf.String()
+}
+
+func TestSlack_payload(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		msg *Message
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+			},
+
+			expected: readFile("testdata/slack/payload00.json"),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: convertReport(unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(), UnitsUS),
+			},
+
+			expected: readFile("testdata/slack/payload01.json"),
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			msg: &Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Text: "\n01/31\n",
+			},
+
+			expected: "{\n  \"text\": \"01/31\"\n}\n",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			msg: alertMessage(tokyo),
+
+			expected: readFile("testdata/slack/payload02.json"),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			n := &slack{}
+			b, err := n.payload(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := indentJSON(b)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func slackFunc(resStatus int, resMessage string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			fmt.Fprint(w, "invalid_method")
+			return
+		}
+
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "invalid_content_type")
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil || !json.Valid(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "invalid_payload")
+			return
+		}
+
+		w.WriteHeader(resStatus)
+		fmt.Fprint(w, resMessage)
+	}
+}
+
+func TestSlack_Notify(t *testing.T) {
+	tests := []struct {
+		resStatus  int
+		resMessage string
+
+		expected error
+	}{
+		// TEST0 {{{
+		{
+			resStatus:  http.StatusOK,
+			resMessage: "ok",
+
+			expected: nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			resStatus:  http.StatusNotFound,
+			resMessage: "no_service",
+
+			expected: slackError{
+				Code:    http.StatusNotFound,
+				Message: "no_service",
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			resStatus:  http.StatusForbidden,
+			resMessage: "action_prohibited\n",
+
+			expected: slackError{
+				Code:    http.StatusForbidden,
+				Message: "action_prohibited",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(slackFunc(tt.resStatus, tt.resMessage)))
+			defer server.Close()
+
+			n := &slack{
+				url:        server.URL + "/services/T000/B000/XXXX",
+				httpClient: server.Client(),
+			}
+
+			err := n.Notify(&Message{Text: "TEST"})
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err != tt.expected {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package weatherline
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	smtpTimeout = 30 * time.Second
+)
+
+var emailTemplate = template.Must(template.Must(forecastTemplate.Clone()).New("email").Parse(`<!DOCTYPE html>
+<html>
+<body>
+{{template "forecast" .}}</body>
+</html>
+`))
+
+type email struct {
+	host     string
+	port     int
+	security SMTPSecurity
+	username string
+	password string
+	from     string
+	to       []string
+
+	tlsConfig *tls.Config
+}
+
+// NewSMTP : Create Notifier instance for E-mail (SMTP)
+//
+// username が空の場合は認証しない。
+func NewSMTP(host string, port int, security SMTPSecurity, username, password, from string, to []string) Notifier {
+	return &email{
+		host:     host,
+		port:     port,
+		security: security,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+	}
+}
+
+// html : HTML 形式の予報 (予報も気象警報もなければ空文字)
+func (n *email) html(msg *Message) (string, error) {
+	return forecastHTML(emailTemplate, msg)
+}
+
+// writeQuotedPrintable : 改行は CRLF になる
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qw := quotedprintable.NewWriter(w)
+	if _, err := qw.Write([]byte(s)); err != nil {
+		return err
+	}
+
+	return qw.Close()
+}
+
+func writePart(w *multipart.Writer, contentType, body string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	pw, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	return writeQuotedPrintable(pw, body)
+}
+
+// payload : メール本文 (ヘッダーを含む)
+//
+// テキスト形式と HTML 形式の multipart/alternative にする。
+// HTML 形式の予報がなければテキストのみ送る。
+func (n *email) payload(msg *Message) ([]byte, error) {
+	from, err := mail.ParseAddress(n.from)
+	if err != nil {
+		return nil, err
+	}
+
+	to := []string{}
+	for _, t := range n.to {
+		addr, err := mail.ParseAddress(t)
+		if err != nil {
+			return nil, err
+		}
+		to = append(to, addr.String())
+	}
+
+	html, err := n.html(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(msg.Text) + "\n"
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", msg.Title()))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if html == "" {
+		buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+		if err := writeQuotedPrintable(&buf, text); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := writePart(w, "text/plain; charset=UTF-8", text); err != nil {
+		return nil, err
+	}
+	if err := writePart(w, "text/html; charset=UTF-8", html); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", w.Boundary())
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func (n *email) config() *tls.Config {
+	config := &tls.Config{}
+	if n.tlsConfig != nil {
+		config = n.tlsConfig.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = n.host
+	}
+
+	return config
+}
+
+func (n *email) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(n.host, strconv.Itoa(n.port))
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	if n.security == SMTPSecurityTLS {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addr, n.config())
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := smtp.NewClient(conn, n.host)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return c, nil
+	}
+
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if n.security == SMTPSecurityStartTLS {
+		if err := c.StartTLS(n.config()); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Notify : Notifier.Notify の実装
+func (n *email) Notify(msg *Message) error {
+	body, err := n.payload(msg)
+	if err != nil {
+		return err
+	}
+
+	c, err := n.dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if n.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.username, n.password, n.host)); err != nil {
+			return err
+		}
+	}
+
+	from, err := mail.ParseAddress(n.from)
+	if err != nil {
+		return err
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+
+	for _, t := range n.to {
+		addr, err := mail.ParseAddress(t)
+		if err != nil {
+			return err
+		}
+		if err := c.Rcpt(addr.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,563 @@
+package weatherline
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewSMTP(t *testing.T) {
+	host := "smtp.example.com"
+	port := 587
+	security := SMTPSecurityStartTLS
+	username := "user"
+	password := "pass"
+	from := "weatherline@example.com"
+	to := []string{"a@example.com", "b@example.com"}
+
+	notifier := NewSMTP(host, port, security, username, password, from, to)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*email)
+	if !ok {
+		t.Fatal("Expected email instance, but not.")
+	}
+
+	if n.host != host {
+		t.Fatalf("Expected host is %s, but it's %s.", host, n.host)
+	}
+
+	if n.port != port {
+		t.Fatalf("Expected port is %d, but it's %d.", port, n.port)
+	}
+
+	if n.security != security {
+		t.Fatalf("Expected security is %s, but it's %s.", security, n.security)
+	}
+
+	if n.username != username || n.password != password {
+		t.Fatalf("Expected username/password is %s/%s, but it's %s/%s.", username, password, n.username, n.password)
+	}
+
+	if n.from != from {
+		t.Fatalf("Expected from is %s, but it's %s.", from, n.from)
+	}
+
+	if !reflect.DeepEqual(n.to, to) {
+		t.Fatalf("Expected to is %v, but it's %v.", to, n.to)
+	}
+}
+
+func TestEmail_html(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		msg *Message
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+			},
+
+			expected: readFile("testdata/smtp/html00.html"),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: convertReport(unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(), UnitsUS),
+			},
+
+			expected: readFile("testdata/smtp/html01.html"),
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			msg: &Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Text: "\n01/31\n",
+			},
+
+			expected: "",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			msg: alertMessage(tokyo),
+
+			expected: readFile("testdata/smtp/html02.html"),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			n := &email{}
+			actual, err := n.html(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// readParts : メールをパースして Content-Type ごとの本文を返す
+func readParts(b []byte) (*mail.Message, map[string]string, error) {
+	m, err := mail.ReadMessage(strings.NewReader(string(b)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parts := map[string]string{}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		body, err := ioutil.ReadAll(quotedprintable.NewReader(m.Body))
+		if err != nil {
+			return nil, nil, err
+		}
+		parts[mediaType] = string(body)
+
+		return m, parts, nil
+	}
+
+	r := multipart.NewReader(m.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err != nil {
+			break
+		}
+
+		t, _, err := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// quoted-printable は multipart.Reader が復号する
+		body, err := ioutil.ReadAll(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		parts[t] = string(body)
+	}
+
+	return m, parts, nil
+}
+
+func TestEmail_payload(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		from string
+		to   []string
+		msg  *Message
+
+		expectedFrom    string
+		expectedTo      string
+		expectedSubject string
+		expectedType    string
+		expectedParts   map[string]string
+	}{
+		// TEST0 {{{
+		{
+			from: "weatherline <weatherline@example.com>",
+			to:   []string{"a@example.com", "B <b@example.com>"},
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+			},
+
+			expectedFrom:    `"weatherline" <weatherline@example.com>`,
+			expectedTo:      `<a@example.com>, "B" <b@example.com>`,
+			expectedSubject: "Weather forecast 01/31 (Wed)",
+			expectedType:    "multipart/alternative",
+			expectedParts: map[string]string{
+				"text/plain": strings.Replace(strings.TrimSpace(readFile("testdata/weatherline/cmd/run00.txt"))+"\n", "\n", "\r\n", -1),
+				"text/html":  strings.Replace(readFile("testdata/smtp/html00.html"), "\n", "\r\n", -1),
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			from: "weatherline@example.com",
+			to:   []string{"a@example.com"},
+			msg: &Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Text: "\n01/31\n",
+			},
+
+			expectedFrom:    "<weatherline@example.com>",
+			expectedTo:      "<a@example.com>",
+			expectedSubject: "Weather forecast 01/31 (Wed)",
+			expectedType:    "text/plain",
+			expectedParts: map[string]string{
+				"text/plain": "01/31\r\n",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			n := &email{from: tt.from, to: tt.to}
+			b, err := n.payload(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m, parts, err := readParts(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual := m.Header.Get("From"); actual != tt.expectedFrom {
+				t.Errorf("Expected From is [%s], but got [%s]", tt.expectedFrom, actual)
+			}
+
+			if actual := m.Header.Get("To"); actual != tt.expectedTo {
+				t.Errorf("Expected To is [%s], but got [%s]", tt.expectedTo, actual)
+			}
+
+			if actual := m.Header.Get("Subject"); actual != tt.expectedSubject {
+				t.Errorf("Expected Subject is [%s], but got [%s]", tt.expectedSubject, actual)
+			}
+
+			if _, err := m.Header.Date(); err != nil {
+				t.Errorf("Invalid Date header (%v)", err)
+			}
+
+			if actual, _, _ := mime.ParseMediaType(m.Header.Get("Content-Type")); actual != tt.expectedType {
+				t.Errorf("Expected Content-Type is [%s], but got [%s]", tt.expectedType, actual)
+			}
+
+			if !reflect.DeepEqual(parts, tt.expectedParts) {
+				t.Errorf("Expected to get %q, but got %q", tt.expectedParts, parts)
+			}
+		})
+	}
+}
+
+// fakeSMTP : テスト用の SMTP サーバー
+type fakeSMTP struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	implicitTLS bool
+	startTLS    bool
+	username    string
+	password    string
+	rejectRcpt  string
+
+	mu   sync.Mutex
+	from string
+	rcpt []string
+	data string
+}
+
+func (s *fakeSMTP) start() error {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	s.listener = l
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return nil
+}
+
+func (s *fakeSMTP) close() {
+	s.listener.Close()
+}
+
+func (s *fakeSMTP) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// angleAddr : "FROM:<a@example.com> BODY=8BITMIME" からアドレスを取り出す
+func angleAddr(arg string) string {
+	start := strings.Index(arg, "<")
+	end := strings.Index(arg, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return arg[start+1 : end]
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	secure := false
+	if s.implicitTLS {
+		conn = tls.Server(conn, s.tlsConfig)
+		secure = true
+	}
+	defer func() { conn.Close() }()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost fake ESMTP")
+
+	authenticated := false
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		args := strings.SplitN(line, " ", 2)
+		arg := ""
+		if len(args) > 1 {
+			arg = args[1]
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250-localhost")
+			if s.startTLS && !secure {
+				tp.PrintfLine("250-STARTTLS")
+			}
+			if s.username != "" {
+				tp.PrintfLine("250-AUTH PLAIN")
+			}
+			tp.PrintfLine("250 8BITMIME")
+		case "STARTTLS":
+			if !s.startTLS || secure {
+				tp.PrintfLine("502 Command not implemented")
+				continue
+			}
+			tp.PrintfLine("220 Ready to start TLS")
+			conn = tls.Server(conn, s.tlsConfig)
+			tp = textproto.NewConn(conn)
+			secure = true
+		case "AUTH":
+			b, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+			if string(b) != fmt.Sprintf("\x00%s\x00%s", s.username, s.password) {
+				tp.PrintfLine("535 Authentication credentials invalid")
+				continue
+			}
+			authenticated = true
+			tp.PrintfLine("235 Authentication successful")
+		case "MAIL":
+			if s.username != "" && !authenticated {
+				tp.PrintfLine("530 Authentication required")
+				continue
+			}
+			s.mu.Lock()
+			s.from = angleAddr(arg)
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			rcpt := angleAddr(arg)
+			if rcpt == s.rejectRcpt {
+				tp.PrintfLine("550 No such user")
+				continue
+			}
+			s.mu.Lock()
+			s.rcpt = append(s.rcpt, rcpt)
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			b, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = string(b)
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestEmail_Notify(t *testing.T) {
+	// 証明書は httptest のものを使う (127.0.0.1 で有効)
+	ts := httptest.NewTLSServer(nil)
+	defer ts.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	tests := []struct {
+		server   *fakeSMTP
+		security SMTPSecurity
+		username string
+		password string
+
+		expectedRcpt []string
+		expected     error
+	}{
+		// TEST0 {{{
+		{
+			server:   &fakeSMTP{},
+			security: SMTPSecurityNone,
+
+			expectedRcpt: []string{"a@example.com", "b@example.com"},
+			expected:     nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			server:   &fakeSMTP{startTLS: true, username: "user", password: "pass"},
+			security: SMTPSecurityStartTLS,
+			username: "user",
+			password: "pass",
+
+			expectedRcpt: []string{"a@example.com", "b@example.com"},
+			expected:     nil,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			server:   &fakeSMTP{implicitTLS: true, username: "user", password: "pass"},
+			security: SMTPSecurityTLS,
+			username: "user",
+			password: "pass",
+
+			expectedRcpt: []string{"a@example.com", "b@example.com"},
+			expected:     nil,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			server:   &fakeSMTP{startTLS: true, username: "user", password: "pass"},
+			security: SMTPSecurityStartTLS,
+			username: "user",
+			password: "invalid",
+
+			expectedRcpt: nil,
+			expected:     &textproto.Error{Code: 535, Msg: "Authentication credentials invalid"},
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			server:   &fakeSMTP{},
+			security: SMTPSecurityStartTLS,
+
+			expectedRcpt: nil,
+			expected:     &textproto.Error{Code: 502, Msg: "Command not implemented"},
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			server:   &fakeSMTP{rejectRcpt: "b@example.com"},
+			security: SMTPSecurityNone,
+
+			expectedRcpt: []string{"a@example.com"},
+			expected:     &textproto.Error{Code: 550, Msg: "No such user"},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			tt.server.tlsConfig = ts.TLS
+			if err := tt.server.start(); err != nil {
+				t.Fatal(err)
+			}
+			defer tt.server.close()
+
+			n := &email{
+				host:     "127.0.0.1",
+				port:     tt.server.port(),
+				security: tt.security,
+				username: tt.username,
+				password: tt.password,
+				from:     "weatherline@example.com",
+				to:       []string{"a@example.com", "b@example.com"},
+
+				tlsConfig: &tls.Config{RootCAs: pool},
+			}
+
+			err := n.Notify(&Message{Text: "TEST"})
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if !reflect.DeepEqual(err, tt.expected) {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+
+			tt.server.mu.Lock()
+			defer tt.server.mu.Unlock()
+
+			if !reflect.DeepEqual(tt.server.rcpt, tt.expectedRcpt) {
+				t.Errorf("Expected recipients are %v, but got %v", tt.expectedRcpt, tt.server.rcpt)
+			}
+
+			if tt.expected != nil {
+				return
+			}
+
+			if tt.server.from != "weatherline@example.com" {
+				t.Errorf("Expected sender is [weatherline@example.com], but got [%s]", tt.server.from)
+			}
+
+			_, parts, err := readParts([]byte(tt.server.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// ReadDotBytes で CRLF は LF になる
+			if parts["text/plain"] != "TEST\n" {
+				t.Errorf("Expected to get [TEST\n], but got [%q]", parts["text/plain"])
+			}
+		})
+	}
+}
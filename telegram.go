@@ -0,0 +1,189 @@
+package weatherline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	telegramAPIBase = "https://api.telegram.org"
+)
+
+// 数値の ID か @channelusername
+var telegramChatIDPattern = regexp.MustCompile(`^(-?[0-9]+|@[A-Za-z][A-Za-z0-9_]{4,})$`)
+
+type telegramError struct {
+	Code        int    `json:"error_code"`
+	Description string `json:"description"`
+}
+
+func (e telegramError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Description)
+}
+
+type telegramRequest struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview,omitempty"`
+}
+
+type telegram struct {
+	token  string
+	chatID string
+
+	url        string
+	httpClient *http.Client
+}
+
+// NewTelegram : Create Notifier instance for Telegram Bot API (sendMessage)
+func NewTelegram(token, chatID string) Notifier {
+	return &telegram{
+		token:  token,
+		chatID: chatID,
+
+		url:        telegramAPIBase,
+		httpClient: &http.Client{},
+	}
+}
+
+// ValidateTelegramChatID : チャット ID (数値または @channelusername) の形式をチェックする
+func ValidateTelegramChatID(chatID string) error {
+	if !telegramChatIDPattern.MatchString(chatID) {
+		return fmt.Errorf("not a chat ID or @channelusername: %s", chatID)
+	}
+
+	return nil
+}
+
+// telegramEscape : MarkdownV2 の特殊文字をエスケープする
+func telegramEscape(s string) string {
+	var buf strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune("\\_*[]()~`>#+-=|{}.!", r) {
+			buf.WriteRune('\\')
+		}
+		buf.WriteRune(r)
+	}
+
+	return buf.String()
+}
+
+// text : MarkdownV2 形式の予報
+//
+// 予報も気象警報もなければテキスト形式の予報をエスケープして返す
+func (n *telegram) text(msg *Message) string {
+	hourly := msg.HourlyPoints()
+	daily := msg.DailyPoints()
+	if len(msg.Alerts) == 0 && len(hourly) == 0 && len(daily) == 0 {
+		return telegramEscape(strings.TrimSpace(msg.Text))
+	}
+
+	f := msg.Format()
+	lines := []string{}
+
+	for _, a := range msg.Alerts {
+		lines = append(lines, fmt.Sprintf("*%s*", telegramEscape(AlertText(a))))
+	}
+	if len(hourly) == 0 && len(daily) == 0 {
+		return strings.Join(lines, "\n")
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, fmt.Sprintf("*%s*", telegramEscape(msg.Date.Format("01/02 (Mon)"))))
+
+	for _, p := range hourly {
+		weather := WeatherText(p.Weather, p.Summary)
+
+		lines = append(lines, fmt.Sprintf("`%s` %s", p.Time.Format("15:04"), telegramEscape(fmt.Sprintf("%s %s/%s %s",
+			weather,
+			f.Temperature(p.Temperature),
+			f.Temperature(p.ApparentTemperature),
+			f.Precip(p.Weather, p.PrecipProbability, p.PrecipAccumulation)))))
+	}
+
+	for _, p := range daily {
+		lines = append(lines,
+			"",
+			fmt.Sprintf("*%s* %s", telegramEscape(p.Time.Format("01/02 (Mon)")), telegramEscape(fmt.Sprintf("%s %s",
+				IconText(p.Weather),
+				f.Precip(p.Weather, p.PrecipProbability, p.PrecipAccumulation)))),
+			telegramEscape(fmt.Sprintf("▲ %s/%s (%s)",
+				f.Temperature(p.TemperatureHigh),
+				f.Temperature(p.ApparentTemperatureHigh),
+				FormatTime("15:04", p.ApparentTemperatureHighTime))),
+			telegramEscape(fmt.Sprintf("▼ %s/%s (%s)",
+				f.Temperature(p.TemperatureLow),
+				f.Temperature(p.ApparentTemperatureLow),
+				FormatTime("15:04", p.ApparentTemperatureLowTime))),
+		)
+		if p.Summary != "" {
+			lines = append(lines, fmt.Sprintf("_%s_", telegramEscape(p.Summary)))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// payload : リクエストボディ
+func (n *telegram) payload(msg *Message) ([]byte, error) {
+	return json.Marshal(telegramRequest{
+		ChatID:                n.chatID,
+		Text:                  n.text(msg),
+		ParseMode:             "MarkdownV2",
+		DisableWebPagePreview: true,
+	})
+}
+
+// Notify : Notifier.Notify の実装
+func (n *telegram) Notify(msg *Message) error {
+	body, err := n.payload(msg)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(n.url)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, fmt.Sprintf("bot%s", n.token), "sendMessage")
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	response := telegramError{}
+	if err := json.Unmarshal(b, &response); err != nil || response.Code == 0 {
+		response.Code = res.StatusCode
+		response.Description = strings.TrimSpace(string(b))
+	}
+
+	return response
+}
@@ -0,0 +1,354 @@
+package weatherline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTelegramError_Error(t *testing.T) {
+	err := telegramError{
+		Code:        400,
+		Description: "Bad Request: chat not found",
+	}
+
+	expected := "400: Bad Request: chat not found"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestNewTelegram(t *testing.T) {
+	token := "123456:ABCDEF"
+	chatID := "-1001234567890"
+
+	notifier := NewTelegram(token, chatID)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*telegram)
+	if !ok {
+		t.Fatal("Expected telegram instance, but not.")
+	}
+
+	if n.token != token {
+		t.Fatalf("Expected token is %s, but it's %s.", token, n.token)
+	}
+
+	if n.chatID != chatID {
+		t.Fatalf("Expected chatID is %s, but it's %s.", chatID, n.chatID)
+	}
+
+	if n.url != telegramAPIBase {
+		t.Fatalf("Expected url is %s, but it's %s.", telegramAPIBase, n.url)
+	}
+
+	if n.httpClient == nil {
+		t.Fatal("httpClient is nil")
+	}
+}
+
+func TestValidateTelegramChatID(t *testing.T) {
+	tests := []struct {
+		chatID string
+
+		expected error
+	}{
+		// TEST0 {{{
+		{
+			chatID: "123456789",
+
+			expected: nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			chatID: "-1001234567890",
+
+			expected: nil,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			chatID: "@weather_channel",
+
+			expected: nil,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			chatID: "weather_channel",
+
+			expected: fmt.Errorf("not a chat ID or @channelusername: weather_channel"),
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			chatID: "",
+
+			expected: fmt.Errorf("not a chat ID or @channelusername: "),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateTelegramChatID(tt.chatID)
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err.Error() != tt.expected.Error() {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+		})
+	}
+}
+
+func TestTelegramEscape(t *testing.T) {
+	tests := []struct {
+		s string
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			s: "一日中曇り。",
+
+			expected: "一日中曇り。",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			s: "01/31 (Wed) -1.8℃",
+
+			expected: `01/31 \(Wed\) \-1\.8℃`,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			s: "_*[]()~`>#+-=|{}.!\\",
+
+			expected: "\\_\\*\\[\\]\\(\\)\\~\\`\\>\\#\\+\\-\\=\\|\\{\\}\\.\\!\\\\",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			s: "夕方から雨（強い）!",
+
+			expected: `夕方から雨（強い）\!`,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := telegramEscape(tt.s)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTelegram_text(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		msg *Message
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			msg: &Message{
+				Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days:   3,
+				Text:   readFile("testdata/weatherline/cmd/run00.txt"),
+				Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+			},
+
+			expected: readFile("testdata/telegram/text00.txt"),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			msg: &Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Text: "\n01/31\n",
+			},
+
+			expected: `01/31`,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			msg: &Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Days: 1,
+				Report: &Report{
+					Daily: []DailyPoint{
+						{
+							Time:                    time.Date(2018, 2, 1, 0, 0, 0, 0, tokyo),
+							Summary:                 "昼過ぎから雨 (所により雷)。",
+							Weather:                 WeatherRain,
+							TemperatureHigh:         9.2,
+							ApparentTemperatureHigh: 7.2,
+							TemperatureLow:          -3.8,
+							ApparentTemperatureLow:  -5.1,
+							PrecipProbability:       0.8,
+						},
+					},
+				},
+			},
+
+			expected: "*01/31 \\(Wed\\)*\n" +
+				"\n" +
+				"*02/01 \\(Thu\\)* ☔ 80%\n" +
+				"▲ 9\\.2℃/7\\.2℃ \\(\\-\\)\n" +
+				"▼ \\-3\\.8℃/\\-5\\.1℃ \\(\\-\\)\n" +
+				"_昼過ぎから雨 \\(所により雷\\)。_",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			msg: alertMessage(tokyo),
+
+			expected: readFile("testdata/telegram/text01.txt"),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			n := &telegram{}
+			actual := n.text(tt.msg)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func telegramFunc(token string, resStatus int, resBody string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			fmt.Fprint(w, `{"ok":false,"error_code":405,"description":"Method Not Allowed"}`)
+			return
+		}
+
+		if r.URL.Path != fmt.Sprintf("/bot%s/sendMessage", token) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"ok":false,"error_code":404,"description":"Not Found"}`)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		req := telegramRequest{}
+		if err := json.Unmarshal(body, &req); err != nil || req.ChatID == "" || req.ParseMode != "MarkdownV2" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: message text is empty"}`)
+			return
+		}
+
+		w.WriteHeader(resStatus)
+		fmt.Fprint(w, resBody)
+	}
+}
+
+func TestTelegram_Notify(t *testing.T) {
+	tests := []struct {
+		token     string
+		resStatus int
+		resBody   string
+
+		expected error
+	}{
+		// TEST0 {{{
+		{
+			token:     "123456:ABCDEF",
+			resStatus: http.StatusOK,
+			resBody:   `{"ok":true,"result":{"message_id":1}}`,
+
+			expected: nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			token:     "123456:ABCDEF",
+			resStatus: http.StatusBadRequest,
+			resBody:   `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`,
+
+			expected: telegramError{
+				Code:        http.StatusBadRequest,
+				Description: "Bad Request: chat not found",
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			token:     "123456:ABCDEF",
+			resStatus: http.StatusBadGateway,
+			resBody:   "Bad Gateway\n",
+
+			expected: telegramError{
+				Code:        http.StatusBadGateway,
+				Description: "Bad Gateway",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(telegramFunc(tt.token, tt.resStatus, tt.resBody)))
+			defer server.Close()
+
+			n := &telegram{
+				token:  tt.token,
+				chatID: "123456789",
+
+				url:        server.URL,
+				httpClient: server.Client(),
+			}
+
+			err := n.Notify(&Message{Text: "TEST"})
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err != tt.expected {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+		})
+	}
+}
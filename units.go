@@ -0,0 +1,328 @@
+package weatherline
+
+import (
+	"math"
+)
+
+const (
+	// 1インチあたりのセンチメートル
+	centimetersPerInch = 2.54
+
+	// 1インチあたりのミリメートル
+	millimetersPerInch = 25.4
+
+	// 1マイルあたりのキロメートル
+	kilometersPerMile = 1.609344
+)
+
+// imperial : 気温が華氏、積雪量がインチの単位系かどうか
+//
+// ca、uk2 は気温と積雪量については si と同じ。
+func (u Units) imperial() bool {
+	return u == UnitsUS
+}
+
+// system : 指定された単位系で得た予報値の単位系 (UnitsUS か UnitsSI)
+//
+// 単位系を選べない API の予報で使う
+func (u Units) system() Units {
+	if u.imperial() {
+		return UnitsUS
+	}
+
+	return UnitsSI
+}
+
+// speedFactor : 風速を m/s に換算する係数
+func (u Units) speedFactor() float64 {
+	switch u {
+	case UnitsUS, UnitsUK2:
+		return kilometersPerMile / 3.6
+	case UnitsCA:
+		return 1 / 3.6
+	default:
+		return 1
+	}
+}
+
+// distanceFactor : 距離を km に換算する係数
+func (u Units) distanceFactor() float64 {
+	switch u {
+	case UnitsUS, UnitsUK2:
+		return kilometersPerMile
+	default:
+		return 1
+	}
+}
+
+// sameSystem : 予報値の単位がすべて同じ単位系かどうか
+func sameSystem(a, b Units) bool {
+	return a.imperial() == b.imperial() && a.speedFactor() == b.speedFactor() && a.distanceFactor() == b.distanceFactor()
+}
+
+// celsius : 華氏を摂氏に変換する
+func celsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
+
+// ConvertTemperature : 気温を from の単位系から to の単位系に変換する
+func ConvertTemperature(v float64, from, to Units) float64 {
+	switch {
+	case from.imperial() == to.imperial():
+		return v
+	case to.imperial():
+		return fahrenheit(v)
+	default:
+		return celsius(v)
+	}
+}
+
+// ConvertAccumulation : 積雪量を from の単位系から to の単位系に変換する
+func ConvertAccumulation(v float64, from, to Units) float64 {
+	switch {
+	case from.imperial() == to.imperial():
+		return v
+	case to.imperial():
+		return v / centimetersPerInch
+	default:
+		return v * centimetersPerInch
+	}
+}
+
+// ConvertIntensity : 降水強度を from の単位系から to の単位系に変換する
+func ConvertIntensity(v float64, from, to Units) float64 {
+	switch {
+	case from.imperial() == to.imperial():
+		return v
+	case to.imperial():
+		return v / millimetersPerInch
+	default:
+		return v * millimetersPerInch
+	}
+}
+
+// ConvertSpeed : 風速を from の単位系から to の単位系に変換する
+func ConvertSpeed(v float64, from, to Units) float64 {
+	if from.speedFactor() == to.speedFactor() {
+		return v
+	}
+
+	return v * from.speedFactor() / to.speedFactor()
+}
+
+// ConvertDistance : 距離を from の単位系から to の単位系に変換する
+func ConvertDistance(v float64, from, to Units) float64 {
+	if from.distanceFactor() == to.distanceFactor() {
+		return v
+	}
+
+	return v * from.distanceFactor() / to.distanceFactor()
+}
+
+// convertConditions : 気象要素を from の単位系から to の単位系に変換する
+func convertConditions(c Conditions, from, to Units) Conditions {
+	c.PrecipIntensity = ConvertIntensity(c.PrecipIntensity, from, to)
+	c.DewPoint = ConvertTemperature(c.DewPoint, from, to)
+	c.WindSpeed = ConvertSpeed(c.WindSpeed, from, to)
+	c.WindGust = ConvertSpeed(c.WindGust, from, to)
+	c.Visibility = ConvertDistance(c.Visibility, from, to)
+
+	return c
+}
+
+// convertReport : 予報値を units の単位系に変換した予報を返す
+func convertReport(r *Report, units Units) *Report {
+	if sameSystem(r.Units, units) {
+		return r
+	}
+
+	temperature := func(v float64) float64 {
+		return ConvertTemperature(v, r.Units, units)
+	}
+	accumulation := func(v float64) float64 {
+		return ConvertAccumulation(v, r.Units, units)
+	}
+
+	hourly := func(p HourlyPoint) HourlyPoint {
+		p.Temperature = temperature(p.Temperature)
+		p.ApparentTemperature = temperature(p.ApparentTemperature)
+		p.PrecipAccumulation = accumulation(p.PrecipAccumulation)
+		if p.Conditions != nil {
+			c := convertConditions(*p.Conditions, r.Units, units)
+			p.Conditions = &c
+		}
+		return p
+	}
+
+	report := *r
+	report.Units = units
+	if r.Currently != nil {
+		p := hourly(*r.Currently)
+		report.Currently = &p
+	}
+	if r.Minutely != nil {
+		report.Minutely = make([]MinutelyPoint, len(r.Minutely))
+		for i, p := range r.Minutely {
+			p.PrecipIntensity = ConvertIntensity(p.PrecipIntensity, r.Units, units)
+			report.Minutely[i] = p
+		}
+	}
+	report.Hourly = make([]HourlyPoint, len(r.Hourly))
+	for i, p := range r.Hourly {
+		report.Hourly[i] = hourly(p)
+	}
+	report.Daily = make([]DailyPoint, len(r.Daily))
+	for i, p := range r.Daily {
+		p.TemperatureHigh = temperature(p.TemperatureHigh)
+		p.TemperatureLow = temperature(p.TemperatureLow)
+		p.ApparentTemperatureHigh = temperature(p.ApparentTemperatureHigh)
+		p.ApparentTemperatureLow = temperature(p.ApparentTemperatureLow)
+		p.PrecipAccumulation = accumulation(p.PrecipAccumulation)
+		if p.Conditions != nil {
+			c := *p.Conditions
+			c.Conditions = convertConditions(c.Conditions, r.Units, units)
+			c.PrecipIntensityMax = ConvertIntensity(c.PrecipIntensityMax, r.Units, units)
+			p.Conditions = &c
+		}
+		report.Daily[i] = p
+	}
+
+	return &report
+}
+
+// 16方位
+var compassPoints = []string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// Formatter : 単位系に合わせて予報値を文字列にする
+//
+// 値が得られない (NaN) 場合は "-" を返す。
+type Formatter struct {
+	units Units
+}
+
+// NewFormatter : units の単位系の予報値を文字列にする Formatter
+//
+// 単位系が不明な場合 (UnitsUnknown、UnitsAuto) は si とみなす。
+func NewFormatter(units Units) Formatter {
+	return Formatter{units: units}
+}
+
+// TemperatureSymbol : 気温の単位
+func (f Formatter) TemperatureSymbol() string {
+	if f.units.imperial() {
+		return "℉"
+	}
+
+	return "℃"
+}
+
+// AccumulationSymbol : 積雪量の単位
+func (f Formatter) AccumulationSymbol() string {
+	if f.units.imperial() {
+		return "in"
+	}
+
+	return "cm"
+}
+
+// IntensitySymbol : 降水強度の単位
+func (f Formatter) IntensitySymbol() string {
+	if f.units.imperial() {
+		return "in/h"
+	}
+
+	return "mm/h"
+}
+
+// SpeedSymbol : 風速の単位
+func (f Formatter) SpeedSymbol() string {
+	switch f.units {
+	case UnitsUS, UnitsUK2:
+		return "mph"
+	case UnitsCA:
+		return "km/h"
+	default:
+		return "m/s"
+	}
+}
+
+// DistanceSymbol : 視程の単位
+func (f Formatter) DistanceSymbol() string {
+	if f.units.distanceFactor() != 1 {
+		return "mi"
+	}
+
+	return "km"
+}
+
+// Temperature : 単位付きの気温 (小数点以下1桁)
+func (f Formatter) Temperature(v float64) string {
+	return FormatValue("%.1f"+f.TemperatureSymbol(), v)
+}
+
+// Accumulation : 単位付きの積雪量 (cm は整数、インチは小数点以下1桁)
+func (f Formatter) Accumulation(v float64) string {
+	format := "%.0f"
+	if f.units.imperial() {
+		format = "%.1f"
+	}
+
+	return FormatValue(format+f.AccumulationSymbol(), v)
+}
+
+// Intensity : 単位付きの降水強度 (mm/h は小数点以下1桁、in/h は2桁)
+func (f Formatter) Intensity(v float64) string {
+	format := "%.1f"
+	if f.units.imperial() {
+		format = "%.2f"
+	}
+
+	return FormatValue(format+f.IntensitySymbol(), v)
+}
+
+// Speed : 単位付きの風速 (小数点以下1桁)
+func (f Formatter) Speed(v float64) string {
+	return FormatValue("%.1f"+f.SpeedSymbol(), v)
+}
+
+// Distance : 単位付きの視程 (小数点以下1桁)
+func (f Formatter) Distance(v float64) string {
+	return FormatValue("%.1f"+f.DistanceSymbol(), v)
+}
+
+// Percent : 割合 (0-1) を百分率にする
+func (f Formatter) Percent(v float64) string {
+	return FormatValue("%.0f%%", v*100)
+}
+
+// Precip : 降水確率 (雪の場合は積雪量も)
+func (f Formatter) Precip(weather Weather, probability, accumulation float64) string {
+	s := f.Percent(probability)
+	if weather == WeatherSnow {
+		s += "/" + f.Accumulation(accumulation)
+	}
+
+	return s
+}
+
+// Pressure : 単位付きの気圧
+func (f Formatter) Pressure(v float64) string {
+	return FormatValue("%.0fhPa", v)
+}
+
+// Bearing : 風向を16方位で表す
+func (f Formatter) Bearing(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+
+	i := int(math.Floor(math.Mod(v, 360)/22.5+0.5)) % len(compassPoints)
+	if i < 0 {
+		i += len(compassPoints)
+	}
+
+	return compassPoints[i]
+}
@@ -0,0 +1,557 @@
+package weatherline
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestConvertTemperature(t *testing.T) {
+	tests := []struct {
+		v    float64
+		from Units
+		to   Units
+
+		expected float64
+	}{
+		// TEST0 {{{
+		{
+			v:    10,
+			from: UnitsSI,
+			to:   UnitsUS,
+
+			expected: 50,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			v:    14,
+			from: UnitsUS,
+			to:   UnitsCA,
+
+			expected: -10,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			v:    -3.5,
+			from: UnitsUK2,
+			to:   UnitsSI,
+
+			expected: -3.5,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			v:    math.NaN(),
+			from: UnitsSI,
+			to:   UnitsUS,
+
+			expected: math.NaN(),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := ConvertTemperature(tt.v, tt.from, tt.to)
+			if !sameValue(actual, tt.expected) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConvertAccumulation(t *testing.T) {
+	tests := []struct {
+		v    float64
+		from Units
+		to   Units
+
+		expected float64
+	}{
+		// TEST0 {{{
+		{
+			v:    2.54,
+			from: UnitsSI,
+			to:   UnitsUS,
+
+			expected: 1,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			v:    2,
+			from: UnitsUS,
+			to:   UnitsUK2,
+
+			expected: 5.08,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			v:    3,
+			from: UnitsCA,
+			to:   UnitsSI,
+
+			expected: 3,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := ConvertAccumulation(tt.v, tt.from, tt.to)
+			if !sameValue(actual, tt.expected) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConvertIntensity(t *testing.T) {
+	tests := []struct {
+		v    float64
+		from Units
+		to   Units
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			v:    25.4,
+			from: UnitsSI,
+			to:   UnitsUS,
+
+			expected: "1.00",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			v:    0.1,
+			from: UnitsUS,
+			to:   UnitsCA,
+
+			expected: "2.54",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			v:    1.5,
+			from: UnitsUK2,
+			to:   UnitsSI,
+
+			expected: "1.50",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := fmt.Sprintf("%.2f", ConvertIntensity(tt.v, tt.from, tt.to))
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConvertSpeed(t *testing.T) {
+	tests := []struct {
+		v    float64
+		from Units
+		to   Units
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			v:    10,
+			from: UnitsSI,
+			to:   UnitsCA,
+
+			expected: "36.00",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			v:    10,
+			from: UnitsUK2,
+			to:   UnitsSI,
+
+			expected: "4.47",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			v:    10,
+			from: UnitsUS,
+			to:   UnitsUK2,
+
+			expected: "10.00",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := fmt.Sprintf("%.2f", ConvertSpeed(tt.v, tt.from, tt.to))
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConvertDistance(t *testing.T) {
+	tests := []struct {
+		v    float64
+		from Units
+		to   Units
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			v:    10,
+			from: UnitsUK2,
+			to:   UnitsCA,
+
+			expected: "16.09",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			v:    16.09344,
+			from: UnitsSI,
+			to:   UnitsUS,
+
+			expected: "10.00",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := fmt.Sprintf("%.2f", ConvertDistance(tt.v, tt.from, tt.to))
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConvertReport(t *testing.T) {
+	day0 := time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	r := &Report{
+		Location: time.UTC,
+		Units:    UnitsUS,
+		Hourly: []HourlyPoint{
+			{Time: day0, Weather: WeatherSnow, Temperature: 32, ApparentTemperature: 23, PrecipProbability: 0.8, PrecipAccumulation: 1, Conditions: &Conditions{DewPoint: 14, Humidity: 0.5, Visibility: 5}},
+		},
+		Daily: []DailyPoint{
+			{Time: day0, Weather: WeatherSnow, TemperatureHigh: 41, TemperatureLow: 14, ApparentTemperatureHigh: 32, ApparentTemperatureLow: math.NaN(), PrecipProbability: 0.8, PrecipAccumulation: 2},
+		},
+	}
+
+	expected := &Report{
+		Location: time.UTC,
+		Units:    UnitsSI,
+		Hourly: []HourlyPoint{
+			{Time: day0, Weather: WeatherSnow, Temperature: 0, ApparentTemperature: -5, PrecipProbability: 0.8, PrecipAccumulation: 2.54},
+		},
+		Daily: []DailyPoint{
+			{Time: day0, Weather: WeatherSnow, TemperatureHigh: 5, TemperatureLow: -10, ApparentTemperatureHigh: 0, ApparentTemperatureLow: math.NaN(), PrecipProbability: 0.8, PrecipAccumulation: 5.08},
+		},
+	}
+
+	actual := convertReport(r, UnitsSI)
+
+	// 気象要素はポインタなので個別に比較する
+	expectedConditions := "{DewPoint:-10.00 Humidity:0.50 Visibility:8.05}"
+	if c := actual.Hourly[0].Conditions; c == nil {
+		t.Error("Expected to get the conditions, but got nil")
+	} else if s := fmt.Sprintf("{DewPoint:%.2f Humidity:%.2f Visibility:%.2f}", c.DewPoint, c.Humidity, c.Visibility); s != expectedConditions {
+		t.Errorf("Expected to get [%s], but got [%s]", expectedConditions, s)
+	}
+	actual.Hourly[0].Conditions = nil
+
+	if !sameValue(actual, expected) {
+		t.Errorf("Expected to get [%+v], but got [%+v]", expected, actual)
+	}
+
+	// 元の予報は変更しない
+	if r.Hourly[0].Temperature != 32 {
+		t.Errorf("Expected not to modify the original report, but got [%+v]", r)
+	}
+
+	if actual := convertReport(r, UnitsUS); actual != r {
+		t.Errorf("Expected to get the same report, but got [%+v]", actual)
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	tests := []struct {
+		units        Units
+		temperature  float64
+		accumulation float64
+
+		expectedTemperature  string
+		expectedAccumulation string
+	}{
+		// TEST0 {{{
+		{
+			units:        UnitsSI,
+			temperature:  -2.34,
+			accumulation: 3.4,
+
+			expectedTemperature:  "-2.3℃",
+			expectedAccumulation: "3cm",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			units:        UnitsUS,
+			temperature:  27.8,
+			accumulation: 1.34,
+
+			expectedTemperature:  "27.8℉",
+			expectedAccumulation: "1.3in",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			units:        UnitsUK2,
+			temperature:  math.NaN(),
+			accumulation: math.NaN(),
+
+			expectedTemperature:  "-",
+			expectedAccumulation: "-",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			units:        UnitsUnknown,
+			temperature:  5,
+			accumulation: 12,
+
+			expectedTemperature:  "5.0℃",
+			expectedAccumulation: "12cm",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			f := NewFormatter(tt.units)
+
+			if actual := f.Temperature(tt.temperature); actual != tt.expectedTemperature {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expectedTemperature, actual)
+			}
+
+			if actual := f.Accumulation(tt.accumulation); actual != tt.expectedAccumulation {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expectedAccumulation, actual)
+			}
+		})
+	}
+}
+
+func TestFormatter_Precip(t *testing.T) {
+	tests := []struct {
+		units        Units
+		weather      Weather
+		probability  float64
+		accumulation float64
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			units:        UnitsSI,
+			weather:      WeatherRain,
+			probability:  0.42,
+			accumulation: 3.4,
+
+			expected: "42%",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			units:        UnitsSI,
+			weather:      WeatherSnow,
+			probability:  0.42,
+			accumulation: 3.4,
+
+			expected: "42%/3cm",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			units:        UnitsUS,
+			weather:      WeatherSnow,
+			probability:  0.42,
+			accumulation: 1.34,
+
+			expected: "42%/1.3in",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			units:        UnitsUS,
+			weather:      WeatherSnow,
+			probability:  math.NaN(),
+			accumulation: math.NaN(),
+
+			expected: "-/-",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := NewFormatter(tt.units).Precip(tt.weather, tt.probability, tt.accumulation)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFormatter_Conditions(t *testing.T) {
+	c := Conditions{
+		PrecipIntensity: 0.25,
+		Humidity:        0.63,
+		Pressure:        1023.6,
+		WindSpeed:       4.12,
+		WindBearing:     290,
+		Visibility:      16.09,
+	}
+
+	tests := []struct {
+		units Units
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			units: UnitsSI,
+
+			expected: "0.2mm/h 63% 1024hPa 4.1m/s WNW 16.1km",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			units: UnitsUS,
+
+			expected: "0.25in/h 63% 1024hPa 4.1mph WNW 16.1mi",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			units: UnitsCA,
+
+			expected: "0.2mm/h 63% 1024hPa 4.1km/h WNW 16.1km",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			units: UnitsUK2,
+
+			expected: "0.2mm/h 63% 1024hPa 4.1mph WNW 16.1mi",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			f := NewFormatter(tt.units)
+
+			actual := fmt.Sprintf("%s %s %s %s %s %s", f.Intensity(c.PrecipIntensity), f.Percent(c.Humidity), f.Pressure(c.Pressure), f.Speed(c.WindSpeed), f.Bearing(c.WindBearing), f.Distance(c.Visibility))
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFormatter_Bearing(t *testing.T) {
+	tests := []struct {
+		v float64
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			v: 0,
+
+			expected: "N",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			v: 350,
+
+			expected: "N",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			v: 135,
+
+			expected: "SE",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			v: -90,
+
+			expected: "W",
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			v: math.NaN(),
+
+			expected: "-",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := NewFormatter(UnitsSI).Bearing(tt.v)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
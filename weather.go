@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
 	"path"
@@ -24,19 +25,50 @@ func (e forecastError) Error() string {
 	return fmt.Sprintf("%d: %s", e.Code, e.Message)
 }
 
-var (
-	excludes = []string{
-		"currently",
-		"minutely",
-		"alerts",
-		"flags",
+// 指定しなければ除外するデータブロック
+var optionalBlocks = []Block{
+	BlockCurrently,
+	BlockMinutely,
+	BlockAlerts,
+	BlockFlags,
+}
+
+// excludes : リクエストで除外するデータブロック
+func excludes(o getOptions) []string {
+	values := []string{}
+	for _, b := range optionalBlocks {
+		if !o.has(b) {
+			values = append(values, b.Value())
+		}
 	}
-)
 
-type timeZone time.Location
+	return values
+}
+
+// TimeZone : IANA のタイムゾーン名 (例: Asia/Tokyo)
+//
+// 名前が不明な場合は UTC になる。
+type TimeZone time.Location
+
+// NewTimeZone : loc のタイムゾーン
+func NewTimeZone(loc *time.Location) TimeZone {
+	return TimeZone(*loc)
+}
+
+// Location : time.Location に変換する
+func (tz *TimeZone) Location() *time.Location {
+	loc := time.Location(*tz)
+	return &loc
+}
+
+// MarshalJSON : json.Marshal のための独自実装 (IANA のタイムゾーン名にする)
+func (tz TimeZone) MarshalJSON() ([]byte, error) {
+	loc := time.Location(tz)
+	return json.Marshal(loc.String())
+}
 
 // UnmarshalJSON : json.Unmarshal のための独自実装
-func (tz *timeZone) UnmarshalJSON(b []byte) error {
+func (tz *TimeZone) UnmarshalJSON(b []byte) error {
 	var s string
 	if err := json.Unmarshal(b, &s); err != nil {
 		return err
@@ -47,61 +79,415 @@ func (tz *timeZone) UnmarshalJSON(b []byte) error {
 		loc = time.UTC
 	}
 
-	*tz = timeZone(*loc)
+	*tz = NewTimeZone(loc)
 
 	return nil
 }
 
-type apiTime struct {
-	time.Time
+// unixTime : UNIX 時間 (値がなければゼロ値)
+func unixTime(t *int64) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return time.Unix(*t, 0)
+}
+
+// optionalValue : 省略可能な予報値 (値がなければ NaN)
+func optionalValue(v *float64) float64 {
+	if v == nil {
+		return math.NaN()
+	}
+
+	return *v
 }
 
-// UnmarshalJSON : json.Unmarshal のための独自実装
-func (t *apiTime) UnmarshalJSON(b []byte) error {
-	var unixTime int64
-	if err := json.Unmarshal(b, &unixTime); err != nil {
-		return err
+// optionalPointer : 省略可能な予報値 (NaN の場合は nil)
+func optionalPointer(v float64) *float64 {
+	if math.IsNaN(v) {
+		return nil
 	}
 
-	*t = apiTime{time.Unix(unixTime, 0)}
+	return &v
+}
 
-	return nil
+// unixSeconds : UNIX 時間 (ゼロ値の場合は nil)
+func unixSeconds(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+
+	sec := t.Unix()
+	return &sec
+}
+
+// inLocation : 地点のタイムゾーンの時刻 (値がなければゼロ値)
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+
+	return t.In(loc)
 }
 
-// ForecastResponse : 天気情報
+// ForecastResponse : forecast API (Dark Sky API) の天気情報
+//
+// 除外したデータブロックはゼロ値 (Currently、Minutely は nil) になる。
 type ForecastResponse struct {
-	TimeZone timeZone  `json:"timezone"`
-	Hourly   dataBlock `json:"hourly"`
-	Daily    dataBlock `json:"daily"`
+	TimeZone  TimeZone      `json:"timezone"`
+	Currently *DataPoint    `json:"currently"`
+	Minutely  *DataBlock    `json:"minutely"`
+	Hourly    DataBlock     `json:"hourly"`
+	Daily     DataBlock     `json:"daily"`
+	Alerts    []AlertObject `json:"alerts"`
+	Flags     Flags         `json:"flags"`
+}
+
+// Location : 予報地点のタイムゾーン
+func (r *ForecastResponse) Location() *time.Location {
+	return r.TimeZone.Location()
+}
+
+// Flags : 予報のメタデータ
+type Flags struct {
+	Units Units `json:"units"` // 予報値の単位系 (units=auto の場合は地点によって決まる)
+}
+
+// Report : 天気予報 API に依存しない形式に変換する
+func (r *ForecastResponse) Report() *Report {
+	loc := r.Location()
+
+	report := &Report{
+		Location: loc,
+		Units:    r.Flags.Units,
+	}
+	if r.Currently != nil {
+		p := r.Currently.hourly(loc)
+		report.Currently = &p
+	}
+	if r.Minutely != nil {
+		report.MinutelySummary = r.Minutely.Summary
+		for _, p := range r.Minutely.Data {
+			report.Minutely = append(report.Minutely, MinutelyPoint{
+				Time:              p.Time.In(loc),
+				PrecipIntensity:   p.PrecipIntensity,
+				PrecipProbability: p.PrecipProbability,
+				PrecipType:        p.PrecipType,
+			})
+		}
+	}
+	for _, p := range r.Hourly.Data {
+		report.Hourly = append(report.Hourly, p.hourly(loc))
+	}
+	for _, p := range r.Daily.Data {
+		report.Daily = append(report.Daily, DailyPoint{
+			Time:                        p.Time.In(loc),
+			Weather:                     p.Weather,
+			Summary:                     p.Summary,
+			TemperatureHigh:             p.TemperatureHigh,
+			TemperatureHighTime:         p.TemperatureHighTime.In(loc),
+			TemperatureLow:              p.TemperatureLow,
+			TemperatureLowTime:          p.TemperatureLowTime.In(loc),
+			ApparentTemperatureHigh:     p.ApparentTemperatureHigh,
+			ApparentTemperatureHighTime: p.ApparentTemperatureHighTime.In(loc),
+			ApparentTemperatureLow:      p.ApparentTemperatureLow,
+			ApparentTemperatureLowTime:  p.ApparentTemperatureLowTime.In(loc),
+			PrecipProbability:           p.PrecipProbability,
+			PrecipAccumulation:          p.PrecipAccumulation,
+			Conditions: &DailyConditions{
+				Conditions:         p.conditions(),
+				SunriseTime:        inLocation(p.SunriseTime, loc),
+				SunsetTime:         inLocation(p.SunsetTime, loc),
+				MoonPhase:          p.MoonPhase,
+				PrecipIntensityMax: p.PrecipIntensityMax,
+			},
+		})
+	}
+
+	for _, a := range r.Alerts {
+		report.Alerts = append(report.Alerts, Alert{
+			Title:       a.Title,
+			Severity:    a.Severity,
+			Regions:     a.Regions,
+			Time:        inLocation(a.Time, loc),
+			Expires:     inLocation(a.Expires, loc),
+			Description: a.Description,
+			URI:         a.URI,
+		})
+	}
+
+	return report
+}
+
+// AlertObject : 気象警報・注意報
+//
+// 時刻は DataPoint と同じく UNIX 時間から変換したもので、値がない時刻はゼロ値になる。
+type AlertObject struct {
+	Title       string    `json:"title"`
+	Severity    string    `json:"severity"` // advisory, watch, warning
+	Regions     []string  `json:"regions"`
+	Time        time.Time `json:"time"`
+	Expires     time.Time `json:"expires"`
+	Description string    `json:"description"`
+	URI         string    `json:"uri"`
+}
+
+// MarshalJSON : json.Marshal のための独自実装 (時刻は UNIX 時間にする)
+func (a AlertObject) MarshalJSON() ([]byte, error) {
+	type alertObject AlertObject
+	return json.Marshal(struct {
+		alertObject
+		Time    *int64 `json:"time,omitempty"`
+		Expires *int64 `json:"expires,omitempty"`
+	}{
+		alertObject: alertObject(a),
+		Time:        unixSeconds(a.Time),
+		Expires:     unixSeconds(a.Expires),
+	})
 }
 
-type dataBlock struct {
-	Data    []dataPoint `json:"data"`
+// UnmarshalJSON : json.Unmarshal のための独自実装
+func (a *AlertObject) UnmarshalJSON(b []byte) error {
+	type alertObject AlertObject
+	v := struct {
+		*alertObject
+		Time    *int64 `json:"time"`
+		Expires *int64 `json:"expires"`
+	}{
+		alertObject: (*alertObject)(a),
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	a.Time = unixTime(v.Time)
+	a.Expires = unixTime(v.Expires)
+
+	return nil
+}
+
+// DataBlock : 一定期間の予報 (hourly、daily)
+type DataBlock struct {
+	Data    []DataPoint `json:"data"`
 	Icon    Weather     `json:"icon"`
 	Summary string      `json:"summary"`
 }
 
-type dataPoint struct {
-	Weather                     Weather `json:"icon"`
-	ApparentTemperature         float64 `json:"apparentTemperature"`         // 体感気温, not on daily
-	ApparentTemperatureHigh     float64 `json:"apparentTemperatureHigh"`     // 最高体感気温, only on daily
-	ApparentTemperatureHighTime apiTime `json:"apparentTemperatureHighTime"` // 最高体感気温時刻, only on daily
-	ApparentTemperatureLow      float64 `json:"apparentTemperatureLow"`      // 最低体感気温, only on daily
-	ApparentTemperatureLowTime  apiTime `json:"apparentTemperatureLowTime"`  // 最低体感気温時刻, only on daily
-	PrecipAccumulation          float64 `json:"precipAccumulation"`          // 積雪量、天気が雪でなければ無視, only on hourly and daily
-	PrecipProbability           float64 `json:"precipProbability"`           // 降水確率
-	Summary                     string  `json:"summary"`
-	Time                        apiTime `json:"time"`
-	Temperature                 float64 `json:"temperature"`         // not in minutely
-	TemperatureHigh             float64 `json:"temperatureHigh"`     // only on daily
-	TemperatureHighTime         apiTime `json:"temperatureHighTime"` // only on daily
-	TemperatureLow              float64 `json:"temperatureLow"`      // only on daily
-	TemperatureLowTime          apiTime `json:"temperatureLowTime"`  // only on daily
+// DataPoint : ある時刻 (daily の場合はその日) の予報
+//
+// 時刻は UNIX 時間から変換したもので、地点のタイムゾーンにするには
+// ForecastResponse.Location を使う。値がない時刻はゼロ値になる。
+// 値がない予報値は NaN になる。
+type DataPoint struct {
+	Weather                     Weather   `json:"icon"`
+	ApparentTemperature         float64   `json:"apparentTemperature"`         // 体感気温, not on daily
+	ApparentTemperatureHigh     float64   `json:"apparentTemperatureHigh"`     // 最高体感気温, only on daily
+	ApparentTemperatureHighTime time.Time `json:"apparentTemperatureHighTime"` // 最高体感気温時刻, only on daily
+	ApparentTemperatureLow      float64   `json:"apparentTemperatureLow"`      // 最低体感気温, only on daily
+	ApparentTemperatureLowTime  time.Time `json:"apparentTemperatureLowTime"`  // 最低体感気温時刻, only on daily
+	PrecipAccumulation          float64   `json:"precipAccumulation"`          // 積雪量、天気が雪でなければ無視, only on hourly and daily
+	PrecipProbability           float64   `json:"precipProbability"`           // 降水確率
+	Summary                     string    `json:"summary"`
+	Time                        time.Time `json:"time"`
+	Temperature                 float64   `json:"temperature"`         // not in minutely
+	TemperatureHigh             float64   `json:"temperatureHigh"`     // only on daily
+	TemperatureHighTime         time.Time `json:"temperatureHighTime"` // only on daily
+	TemperatureLow              float64   `json:"temperatureLow"`      // only on daily
+	TemperatureLowTime          time.Time `json:"temperatureLowTime"`  // only on daily
+	PrecipIntensity             float64   `json:"precipIntensity"`
+	PrecipIntensityMax          float64   `json:"precipIntensityMax"` // only on daily
+	PrecipType                  string    `json:"precipType"`
+	DewPoint                    float64   `json:"dewPoint"`
+	Humidity                    float64   `json:"humidity"`
+	Pressure                    float64   `json:"pressure"`
+	WindSpeed                   float64   `json:"windSpeed"`
+	WindGust                    float64   `json:"windGust"`
+	WindBearing                 float64   `json:"windBearing"`
+	CloudCover                  float64   `json:"cloudCover"`
+	UVIndex                     float64   `json:"uvIndex"`
+	Visibility                  float64   `json:"visibility"`
+	Ozone                       float64   `json:"ozone"`
+	SunriseTime                 time.Time `json:"sunriseTime"` // only on daily
+	SunsetTime                  time.Time `json:"sunsetTime"`  // only on daily
+	MoonPhase                   float64   `json:"moonPhase"`   // only on daily
+}
+
+// MarshalJSON : json.Marshal のための独自実装
+//
+// 時刻は UNIX 時間にし、値がない時刻や気象要素は省略する。
+func (p DataPoint) MarshalJSON() ([]byte, error) {
+	type dataPoint DataPoint
+	return json.Marshal(struct {
+		dataPoint
+		Time                        *int64 `json:"time,omitempty"`
+		TemperatureHighTime         *int64 `json:"temperatureHighTime,omitempty"`
+		TemperatureLowTime          *int64 `json:"temperatureLowTime,omitempty"`
+		ApparentTemperatureHighTime *int64 `json:"apparentTemperatureHighTime,omitempty"`
+		ApparentTemperatureLowTime  *int64 `json:"apparentTemperatureLowTime,omitempty"`
+		SunriseTime                 *int64 `json:"sunriseTime,omitempty"`
+		SunsetTime                  *int64 `json:"sunsetTime,omitempty"`
+
+		Temperature             *float64 `json:"temperature,omitempty"`
+		ApparentTemperature     *float64 `json:"apparentTemperature,omitempty"`
+		TemperatureHigh         *float64 `json:"temperatureHigh,omitempty"`
+		TemperatureLow          *float64 `json:"temperatureLow,omitempty"`
+		ApparentTemperatureHigh *float64 `json:"apparentTemperatureHigh,omitempty"`
+		ApparentTemperatureLow  *float64 `json:"apparentTemperatureLow,omitempty"`
+		PrecipProbability       *float64 `json:"precipProbability,omitempty"`
+		PrecipAccumulation      *float64 `json:"precipAccumulation,omitempty"`
+		PrecipIntensity         *float64 `json:"precipIntensity,omitempty"`
+		PrecipIntensityMax      *float64 `json:"precipIntensityMax,omitempty"`
+		DewPoint                *float64 `json:"dewPoint,omitempty"`
+		Humidity                *float64 `json:"humidity,omitempty"`
+		Pressure                *float64 `json:"pressure,omitempty"`
+		WindSpeed               *float64 `json:"windSpeed,omitempty"`
+		WindGust                *float64 `json:"windGust,omitempty"`
+		WindBearing             *float64 `json:"windBearing,omitempty"`
+		CloudCover              *float64 `json:"cloudCover,omitempty"`
+		UVIndex                 *float64 `json:"uvIndex,omitempty"`
+		Visibility              *float64 `json:"visibility,omitempty"`
+		Ozone                   *float64 `json:"ozone,omitempty"`
+		MoonPhase               *float64 `json:"moonPhase,omitempty"`
+	}{
+		dataPoint: dataPoint(p),
+
+		Time:                        unixSeconds(p.Time),
+		TemperatureHighTime:         unixSeconds(p.TemperatureHighTime),
+		TemperatureLowTime:          unixSeconds(p.TemperatureLowTime),
+		ApparentTemperatureHighTime: unixSeconds(p.ApparentTemperatureHighTime),
+		ApparentTemperatureLowTime:  unixSeconds(p.ApparentTemperatureLowTime),
+		SunriseTime:                 unixSeconds(p.SunriseTime),
+		SunsetTime:                  unixSeconds(p.SunsetTime),
+
+		Temperature:             optionalPointer(p.Temperature),
+		ApparentTemperature:     optionalPointer(p.ApparentTemperature),
+		TemperatureHigh:         optionalPointer(p.TemperatureHigh),
+		TemperatureLow:          optionalPointer(p.TemperatureLow),
+		ApparentTemperatureHigh: optionalPointer(p.ApparentTemperatureHigh),
+		ApparentTemperatureLow:  optionalPointer(p.ApparentTemperatureLow),
+		PrecipProbability:       optionalPointer(p.PrecipProbability),
+		PrecipAccumulation:      optionalPointer(p.PrecipAccumulation),
+		PrecipIntensity:         optionalPointer(p.PrecipIntensity),
+		PrecipIntensityMax:      optionalPointer(p.PrecipIntensityMax),
+		DewPoint:                optionalPointer(p.DewPoint),
+		Humidity:                optionalPointer(p.Humidity),
+		Pressure:                optionalPointer(p.Pressure),
+		WindSpeed:               optionalPointer(p.WindSpeed),
+		WindGust:                optionalPointer(p.WindGust),
+		WindBearing:             optionalPointer(p.WindBearing),
+		CloudCover:              optionalPointer(p.CloudCover),
+		UVIndex:                 optionalPointer(p.UVIndex),
+		Visibility:              optionalPointer(p.Visibility),
+		Ozone:                   optionalPointer(p.Ozone),
+		MoonPhase:               optionalPointer(p.MoonPhase),
+	})
+}
+
+// UnmarshalJSON : json.Unmarshal のための独自実装
+func (p *DataPoint) UnmarshalJSON(b []byte) error {
+	type dataPoint DataPoint
+	v := struct {
+		*dataPoint
+		Time                        *int64 `json:"time"`
+		TemperatureHighTime         *int64 `json:"temperatureHighTime"`
+		TemperatureLowTime          *int64 `json:"temperatureLowTime"`
+		ApparentTemperatureHighTime *int64 `json:"apparentTemperatureHighTime"`
+		ApparentTemperatureLowTime  *int64 `json:"apparentTemperatureLowTime"`
+		SunriseTime                 *int64 `json:"sunriseTime"`
+		SunsetTime                  *int64 `json:"sunsetTime"`
+
+		Temperature             *float64 `json:"temperature"`
+		ApparentTemperature     *float64 `json:"apparentTemperature"`
+		TemperatureHigh         *float64 `json:"temperatureHigh"`
+		TemperatureLow          *float64 `json:"temperatureLow"`
+		ApparentTemperatureHigh *float64 `json:"apparentTemperatureHigh"`
+		ApparentTemperatureLow  *float64 `json:"apparentTemperatureLow"`
+		PrecipProbability       *float64 `json:"precipProbability"`
+		PrecipAccumulation      *float64 `json:"precipAccumulation"`
+		PrecipIntensity         *float64 `json:"precipIntensity"`
+		PrecipIntensityMax      *float64 `json:"precipIntensityMax"`
+		DewPoint                *float64 `json:"dewPoint"`
+		Humidity                *float64 `json:"humidity"`
+		Pressure                *float64 `json:"pressure"`
+		WindSpeed               *float64 `json:"windSpeed"`
+		WindGust                *float64 `json:"windGust"`
+		WindBearing             *float64 `json:"windBearing"`
+		CloudCover              *float64 `json:"cloudCover"`
+		UVIndex                 *float64 `json:"uvIndex"`
+		Visibility              *float64 `json:"visibility"`
+		Ozone                   *float64 `json:"ozone"`
+		MoonPhase               *float64 `json:"moonPhase"`
+	}{
+		dataPoint: (*dataPoint)(p),
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	p.Time = unixTime(v.Time)
+	p.TemperatureHighTime = unixTime(v.TemperatureHighTime)
+	p.TemperatureLowTime = unixTime(v.TemperatureLowTime)
+	p.ApparentTemperatureHighTime = unixTime(v.ApparentTemperatureHighTime)
+	p.ApparentTemperatureLowTime = unixTime(v.ApparentTemperatureLowTime)
+	p.SunriseTime = unixTime(v.SunriseTime)
+	p.SunsetTime = unixTime(v.SunsetTime)
+
+	p.Temperature = optionalValue(v.Temperature)
+	p.ApparentTemperature = optionalValue(v.ApparentTemperature)
+	p.TemperatureHigh = optionalValue(v.TemperatureHigh)
+	p.TemperatureLow = optionalValue(v.TemperatureLow)
+	p.ApparentTemperatureHigh = optionalValue(v.ApparentTemperatureHigh)
+	p.ApparentTemperatureLow = optionalValue(v.ApparentTemperatureLow)
+	p.PrecipProbability = optionalValue(v.PrecipProbability)
+	p.PrecipAccumulation = optionalValue(v.PrecipAccumulation)
+	p.PrecipIntensity = optionalValue(v.PrecipIntensity)
+	p.PrecipIntensityMax = optionalValue(v.PrecipIntensityMax)
+	p.DewPoint = optionalValue(v.DewPoint)
+	p.Humidity = optionalValue(v.Humidity)
+	p.Pressure = optionalValue(v.Pressure)
+	p.WindSpeed = optionalValue(v.WindSpeed)
+	p.WindGust = optionalValue(v.WindGust)
+	p.WindBearing = optionalValue(v.WindBearing)
+	p.CloudCover = optionalValue(v.CloudCover)
+	p.UVIndex = optionalValue(v.UVIndex)
+	p.Visibility = optionalValue(v.Visibility)
+	p.Ozone = optionalValue(v.Ozone)
+	p.MoonPhase = optionalValue(v.MoonPhase)
+
+	return nil
+}
+
+// hourly : 時間別予報 (currently も同じ形式)
+func (p *DataPoint) hourly(loc *time.Location) HourlyPoint {
+	conditions := p.conditions()
+	return HourlyPoint{
+		Time:                p.Time.In(loc),
+		Weather:             p.Weather,
+		Summary:             p.Summary,
+		Temperature:         p.Temperature,
+		ApparentTemperature: p.ApparentTemperature,
+		PrecipProbability:   p.PrecipProbability,
+		PrecipAccumulation:  p.PrecipAccumulation,
+		Conditions:          &conditions,
+	}
 }
 
-// Forecast : forecast API (Dark Sky API) client interface
-type Forecast interface {
-	Get(Lang, Units) (*ForecastResponse, error)
+// conditions : 気温・降水確率以外の気象要素
+func (p *DataPoint) conditions() Conditions {
+	return Conditions{
+		PrecipIntensity: p.PrecipIntensity,
+		PrecipType:      p.PrecipType,
+		DewPoint:        p.DewPoint,
+		Humidity:        p.Humidity,
+		Pressure:        p.Pressure,
+		WindSpeed:       p.WindSpeed,
+		WindGust:        p.WindGust,
+		WindBearing:     p.WindBearing,
+		CloudCover:      p.CloudCover,
+		UVIndex:         p.UVIndex,
+		Visibility:      p.Visibility,
+		Ozone:           p.Ozone,
+	}
 }
 
 type forecast struct {
@@ -109,9 +495,51 @@ type forecast struct {
 	httpClient *http.Client
 }
 
-// NewForecast : Create Forecast instance
-func NewForecast(token, lat, long string) Forecast {
-	u, err := url.Parse(forecastAPIBase)
+type forecastOptions struct {
+	baseURL string
+}
+
+// ForecastOption : NewForecast のオプション
+type ForecastOption func(*forecastOptions)
+
+// WithBaseURL : Dark Sky 互換の API (Pirate Weather など) の URL を指定する
+//
+// URL は絶対 URL でなければならない。
+// パスを含む場合はその下の /forecast/{token}/{lat},{long} にリクエストする。
+func WithBaseURL(base string) ForecastOption {
+	return func(o *forecastOptions) {
+		o.baseURL = base
+	}
+}
+
+// ValidateBaseURL : WithBaseURL に指定できる URL かどうかを検査する
+func ValidateBaseURL(base string) error {
+	u, err := url.Parse(base)
+	if err != nil {
+		return err
+	}
+
+	if !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("not an absolute URL: %s", base)
+	}
+
+	return nil
+}
+
+// NewForecast : Create Forecast instance for forecast API (Dark Sky API)
+func NewForecast(token, lat, long string, opts ...ForecastOption) Forecast {
+	o := forecastOptions{
+		baseURL: forecastAPIBase,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := ValidateBaseURL(o.baseURL); err != nil {
+		return nil
+	}
+
+	u, err := url.Parse(o.baseURL)
 	if err != nil {
 		return nil
 	}
@@ -123,8 +551,26 @@ func NewForecast(token, lat, long string) Forecast {
 	}
 }
 
-// Get : ForecastClient.Get の実装
-func (f *forecast) Get(lang Lang, units Units) (*ForecastResponse, error) {
+// Get : Forecast.Get の実装
+func (f *forecast) Get(lang Lang, units Units, opts ...GetOption) (*Report, error) {
+	r, err := f.fetch(lang, units, newGetOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	report := r.Report()
+	if report.Units == UnitsUnknown && units != UnitsAuto {
+		// flags がない場合は指定した単位系 (指定しなければ API の既定の us)
+		report.Units = units
+		if units == UnitsUnknown {
+			report.Units = UnitsUS
+		}
+	}
+
+	return report, nil
+}
+
+func (f *forecast) fetch(lang Lang, units Units, o getOptions) (*ForecastResponse, error) {
 	values := url.Values{}
 	if lang != LangUnknown {
 		values.Set("lang", lang.Value())
@@ -132,7 +578,12 @@ func (f *forecast) Get(lang Lang, units Units) (*ForecastResponse, error) {
 	if units != UnitsUnknown {
 		values.Set("units", units.Value())
 	}
-	values.Set("exclude", strings.Join(excludes, ","))
+	if e := excludes(o); len(e) > 0 {
+		values.Set("exclude", strings.Join(e, ","))
+	}
+	if o.extendHourly {
+		values.Set("extend", "hourly")
+	}
 
 	u := *f.url
 	u.RawQuery = values.Encode()
@@ -4,12 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	neturl "net/url"
 	"reflect"
 	"regexp"
-	"strings"
 	"testing"
 	"time"
 )
@@ -41,20 +41,20 @@ func TestTimeZone_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
 		json []byte
 
-		expected    timeZone
+		expected    TimeZone
 		expectError bool
 	}{
 		// TEST0 {{{
 		{
 			json:        []byte(`"Asia/Tokyo"`),
-			expected:    timeZone(*loadLocation("Asia/Tokyo")),
+			expected:    NewTimeZone(loadLocation("Asia/Tokyo")),
 			expectError: false,
 		},
 		// }}}
 		// TEST1 {{{
 		{
 			json:        []byte(`"unknown-location"`),
-			expected:    timeZone(*time.UTC),
+			expected:    NewTimeZone(time.UTC),
 			expectError: false,
 		},
 		// }}}
@@ -71,7 +71,7 @@ func TestTimeZone_UnmarshalJSON(t *testing.T) {
 		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
 			t.Parallel()
 
-			var tz timeZone
+			var tz TimeZone
 			err := tz.UnmarshalJSON(tt.json)
 			if err != nil {
 				if !tt.expectError {
@@ -92,33 +92,99 @@ func TestTimeZone_UnmarshalJSON(t *testing.T) {
 	}
 }
 
-func TestAPITime_UnmarshalJSON(t *testing.T) {
+func TestTimeZone_Location(t *testing.T) {
+	tz := NewTimeZone(loadLocation("Asia/Tokyo"))
+
+	expected := "Asia/Tokyo"
+
+	actual := tz.Location().String()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+// missingDataPoint : 予報値がすべて得られなかった DataPoint
+func missingDataPoint() DataPoint {
+	return DataPoint{
+		ApparentTemperature:     math.NaN(),
+		ApparentTemperatureHigh: math.NaN(),
+		ApparentTemperatureLow:  math.NaN(),
+		PrecipAccumulation:      math.NaN(),
+		PrecipProbability:       math.NaN(),
+		Temperature:             math.NaN(),
+		TemperatureHigh:         math.NaN(),
+		TemperatureLow:          math.NaN(),
+		PrecipIntensity:         math.NaN(),
+		PrecipIntensityMax:      math.NaN(),
+		DewPoint:                math.NaN(),
+		Humidity:                math.NaN(),
+		Pressure:                math.NaN(),
+		WindSpeed:               math.NaN(),
+		WindGust:                math.NaN(),
+		WindBearing:             math.NaN(),
+		CloudCover:              math.NaN(),
+		UVIndex:                 math.NaN(),
+		Visibility:              math.NaN(),
+		Ozone:                   math.NaN(),
+		MoonPhase:               math.NaN(),
+	}
+}
+
+func TestDataPoint_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
 		json []byte
 
-		expected    apiTime
+		expected    DataPoint
 		expectError bool
 	}{
 		// TEST0 {{{
 		{
-			json:        []byte(`1517138160`),
-			expected:    apiTime{time.Unix(1517138160, 0)}, // 2018/01/01
+			json: []byte(`{"time": 1517138160, "icon": "snow", "temperature": -1.5}`),
+			expected: func() DataPoint {
+				p := missingDataPoint()
+				p.Time = time.Unix(1517138160, 0) // 2018/01/28
+				p.Weather = WeatherSnow
+				p.Temperature = -1.5
+				return p
+			}(),
 			expectError: false,
 		},
 		// }}}
 		// TEST1 {{{
 		{
-			json:        []byte(`0`),
-			expected:    apiTime{time.Unix(0, 0)}, // 1970/01/01
+			json: []byte(`{"time": 0, "sunriseTime": 1517348880, "moonPhase": 0.5, "uvIndex": 0, "windGust": 3.2}`),
+			expected: func() DataPoint {
+				p := missingDataPoint()
+				p.Time = time.Unix(0, 0) // 1970/01/01
+				p.SunriseTime = time.Unix(1517348880, 0)
+				p.MoonPhase = 0.5
+				p.UVIndex = 0
+				p.WindGust = 3.2
+				return p
+			}(),
 			expectError: false,
 		},
 		// }}}
 		// TEST2 {{{
 		{
-			json:        []byte(`""`),
+			json:        []byte(`{"time": ""}`),
 			expectError: true,
 		},
 		// }}}
+		// TEST3 {{{
+		{
+			json: []byte(`{"time": 1517410800, "icon": "rain", "summary": "雨", "precipIntensity": 0.5}`),
+			expected: func() DataPoint {
+				p := missingDataPoint()
+				p.Time = time.Unix(1517410800, 0)
+				p.Weather = WeatherRain
+				p.Summary = "雨"
+				p.PrecipIntensity = 0.5
+				return p
+			}(),
+			expectError: false,
+		},
+		// }}}
 	}
 
 	for i, tt := range tests {
@@ -126,8 +192,8 @@ func TestAPITime_UnmarshalJSON(t *testing.T) {
 		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
 			t.Parallel()
 
-			var tim apiTime
-			err := tim.UnmarshalJSON(tt.json)
+			var p DataPoint
+			err := json.Unmarshal(tt.json, &p)
 			if err != nil {
 				if !tt.expectError {
 					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
@@ -140,8 +206,75 @@ func TestAPITime_UnmarshalJSON(t *testing.T) {
 				return
 			}
 
-			if !reflect.DeepEqual(tim, tt.expected) {
-				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected, err)
+			if !sameDeep(p, tt.expected) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected, p)
+			}
+		})
+	}
+}
+
+func TestAlertObject_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json []byte
+
+		expected    AlertObject
+		expectError bool
+	}{
+		// TEST0 {{{
+		{
+			json: []byte(`{"title":"大雪警報","regions":["名古屋市"],"severity":"warning","time":1517356800,"expires":1517400000,"description":"大雪に警戒してください。","uri":"https://www.jma.go.jp/"}`),
+			expected: AlertObject{
+				Title:       "大雪警報",
+				Severity:    "warning",
+				Regions:     []string{"名古屋市"},
+				Time:        time.Unix(1517356800, 0),
+				Expires:     time.Unix(1517400000, 0),
+				Description: "大雪に警戒してください。",
+				URI:         "https://www.jma.go.jp/",
+			},
+			expectError: false,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			json: []byte(`{"title":"Typhoon Watch","severity":"watch","time":1517356800}`),
+			expected: AlertObject{
+				Title:    "Typhoon Watch",
+				Severity: "watch",
+				Time:     time.Unix(1517356800, 0),
+			},
+			expectError: false,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			json:        []byte(`{"expires":"tomorrow"}`),
+			expectError: true,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			var a AlertObject
+			err := json.Unmarshal(tt.json, &a)
+			if err != nil {
+				if !tt.expectError {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				}
+				return
+			}
+
+			if tt.expectError {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+				return
+			}
+
+			if !reflect.DeepEqual(a, tt.expected) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected, a)
 			}
 		})
 	}
@@ -152,26 +285,130 @@ func TestNewForecast(t *testing.T) {
 	lat := "123.45"
 	long := "67.890"
 
-	fore := NewForecast(token, lat, long)
+	tests := []struct {
+		opts []ForecastOption
 
-	if fore == nil {
-		t.Fatal("function returns nil")
+		expectedURL string
+	}{
+		// TEST0 {{{
+		{
+			expectedURL: fmt.Sprintf("%s/forecast/%s/%s,%s", forecastAPIBase, token, lat, long),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			opts: []ForecastOption{WithBaseURL("https://api.pirateweather.net")},
+
+			expectedURL: fmt.Sprintf("https://api.pirateweather.net/forecast/%s/%s,%s", token, lat, long),
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			opts: []ForecastOption{WithBaseURL("http://localhost:8080/darksky/")},
+
+			expectedURL: fmt.Sprintf("http://localhost:8080/darksky/forecast/%s/%s,%s", token, lat, long),
+		},
+		// }}}
 	}
 
-	f, ok := fore.(*forecast)
-	if !ok {
-		t.Fatal("Expected lineNotify instance, but not.")
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			fore := NewForecast(token, lat, long, tt.opts...)
+
+			if fore == nil {
+				t.Fatal("function returns nil")
+			}
+
+			f, ok := fore.(*forecast)
+			if !ok {
+				t.Fatal("Expected lineNotify instance, but not.")
+			}
+
+			if f.url == nil {
+				t.Fatal("url is nil")
+			} else if f.url.String() != tt.expectedURL {
+				t.Fatalf("Expected url is %s, but it's %s.", tt.expectedURL, f.url.String())
+			}
+
+			if f.httpClient == nil {
+				t.Fatal("httpClient is nil")
+			}
+		})
 	}
+}
 
-	expectedURL := fmt.Sprintf("%s/forecast/%s/%s,%s", forecastAPIBase, token, lat, long)
-	if f.url == nil {
-		t.Fatal("url is nil")
-	} else if f.url.String() != expectedURL {
-		t.Fatalf("Expected url is %s, but it's %s.", expectedURL, f.url.String())
+func TestNewForecast_invalidBaseURL(t *testing.T) {
+	fore := NewForecast("abcde", "123.45", "67.890", WithBaseURL("/darksky"))
+	if fore != nil {
+		t.Fatalf("Expected nil, but got %v", fore)
 	}
+}
 
-	if f.httpClient == nil {
-		t.Fatal("httpClient is nil")
+func TestValidateBaseURL(t *testing.T) {
+	tests := []struct {
+		base string
+
+		expectedError bool
+	}{
+		// TEST0 {{{
+		{
+			base: "https://api.pirateweather.net",
+
+			expectedError: false,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			base: "http://localhost:8080/darksky",
+
+			expectedError: false,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			base: "api.pirateweather.net",
+
+			expectedError: true,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			base: "/darksky",
+
+			expectedError: true,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			base: "https://",
+
+			expectedError: true,
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			base: "%zz",
+
+			expectedError: true,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateBaseURL(tt.base)
+			if tt.expectedError && err == nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			} else if !tt.expectedError && err != nil {
+				t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+			}
+		})
 	}
 }
 
@@ -209,7 +446,7 @@ func writeForecastErrorResponse(w http.ResponseWriter, status int, message strin
 	}
 }
 
-func forecastFunc(lang Lang, units Units, resStatus int, response string) func(http.ResponseWriter, *http.Request) {
+func forecastFunc(lang Lang, units Units, exclude, extend string, resStatus int, response string) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			writeForecastErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Unexpected request: method = %s", r.Method))
@@ -218,7 +455,7 @@ func forecastFunc(lang Lang, units Units, resStatus int, response string) func(h
 
 		url := r.URL
 
-		err := checkQuery(lang, units, url.Query())
+		err := checkQuery(lang, units, exclude, extend, url.Query())
 		if err != nil {
 			writeForecastErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
@@ -234,7 +471,7 @@ func forecastFunc(lang Lang, units Units, resStatus int, response string) func(h
 	}
 }
 
-func checkQuery(lang Lang, units Units, query neturl.Values) error {
+func checkQuery(lang Lang, units Units, exclude, extend string, query neturl.Values) error {
 	val := query.Get("lang")
 	if val != lang.Value() {
 		return fmt.Errorf("Unexpected request: `lang` in query = %s", val)
@@ -244,10 +481,14 @@ func checkQuery(lang Lang, units Units, query neturl.Values) error {
 		return fmt.Errorf("Unexpected request: `units` in query = %s", val)
 	}
 
-	if val := query.Get("exclude"); val != strings.Join(excludes, ",") {
+	if val := query.Get("exclude"); val != exclude {
 		return fmt.Errorf("Unexpected request: `exclude` in query = %s", val)
 	}
 
+	if val := query.Get("extend"); val != extend {
+		return fmt.Errorf("Unexpected request: `extend` in query = %s", val)
+	}
+
 	return nil
 }
 
@@ -260,15 +501,23 @@ func unmarshal(str string) *ForecastResponse {
 	return r
 }
 
-func TestForecast_Send(t *testing.T) {
+func reportIn(r *Report, units Units) *Report {
+	r.Units = units
+	return r
+}
+
+func TestForecast_Get(t *testing.T) {
 	tests := []struct {
 		lang  Lang
 		units Units
+		opts  []GetOption
 
+		exclude    string
+		extend     string
 		resStatus  int
 		resMessage string
 
-		expectedResponse *ForecastResponse
+		expectedResponse *Report
 		expectedError    error
 	}{
 		// TEST0 {{{
@@ -276,10 +525,11 @@ func TestForecast_Send(t *testing.T) {
 			lang:  LangJa,
 			units: UnitsSI,
 
+			exclude:    "currently,minutely",
 			resStatus:  http.StatusOK,
 			resMessage: readFile("testdata/forecast/get00.json"),
 
-			expectedResponse: unmarshal(readFile("testdata/forecast/get00.json")),
+			expectedResponse: reportIn(unmarshal(readFile("testdata/forecast/get00.json")).Report(), UnitsSI),
 			expectedError:    nil,
 		},
 		// }}}
@@ -288,10 +538,11 @@ func TestForecast_Send(t *testing.T) {
 			lang:  LangEn,
 			units: UnitsUS,
 
+			exclude:    "currently,minutely",
 			resStatus:  http.StatusOK,
 			resMessage: readFile("testdata/forecast/get01.json"),
 
-			expectedResponse: unmarshal(readFile("testdata/forecast/get01.json")),
+			expectedResponse: reportIn(unmarshal(readFile("testdata/forecast/get01.json")).Report(), UnitsUS),
 			expectedError:    nil,
 		},
 		// }}}
@@ -300,6 +551,7 @@ func TestForecast_Send(t *testing.T) {
 			lang:  LangJa,
 			units: UnitsSI,
 
+			exclude:    "currently,minutely",
 			resStatus:  400,
 			resMessage: "This error is expected",
 
@@ -311,6 +563,7 @@ func TestForecast_Send(t *testing.T) {
 		// }}}
 		// TEST3 {{{
 		{
+			exclude:    "currently,minutely",
 			resStatus:  http.StatusInternalServerError,
 			resMessage: "This error is expected 2",
 
@@ -320,6 +573,62 @@ func TestForecast_Send(t *testing.T) {
 			},
 		},
 		// }}}
+		// TEST4 {{{
+		{
+			lang:  LangEn,
+			units: UnitsAuto,
+
+			exclude:    "currently,minutely",
+			resStatus:  http.StatusOK,
+			resMessage: readFile("testdata/forecast/get02.json"),
+
+			expectedResponse: reportIn(unmarshal(readFile("testdata/forecast/get02.json")).Report(), UnitsCA),
+			expectedError:    nil,
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			lang:  LangJa,
+			units: UnitsSI,
+			opts:  []GetOption{WithBlocks(BlockCurrently, BlockMinutely, BlockFlags)},
+
+			exclude:    "alerts",
+			resStatus:  http.StatusOK,
+			resMessage: readFile("testdata/forecast/get03.json"),
+
+			expectedResponse: unmarshal(readFile("testdata/forecast/get03.json")).Report(),
+			expectedError:    nil,
+		},
+		// }}}
+		// TEST6 {{{
+		{
+			lang:  LangJa,
+			units: UnitsSI,
+			opts:  []GetOption{WithBlocks(BlockCurrently, BlockMinutely, BlockAlerts, BlockFlags)},
+
+			exclude:    "",
+			resStatus:  http.StatusOK,
+			resMessage: readFile("testdata/forecast/get03.json"),
+
+			expectedResponse: unmarshal(readFile("testdata/forecast/get03.json")).Report(),
+			expectedError:    nil,
+		},
+		// }}}
+		// TEST7 {{{
+		{
+			lang:  LangEn,
+			units: UnitsUS,
+			opts:  []GetOption{WithExtendHourly()},
+
+			exclude:    "currently,minutely",
+			extend:     "hourly",
+			resStatus:  http.StatusOK,
+			resMessage: readFile("testdata/forecast/get01.json"),
+
+			expectedResponse: reportIn(unmarshal(readFile("testdata/forecast/get01.json")).Report(), UnitsUS),
+			expectedError:    nil,
+		},
+		// }}}
 	}
 
 	for i, tt := range tests {
@@ -327,7 +636,7 @@ func TestForecast_Send(t *testing.T) {
 		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
 			t.Parallel()
 
-			server := httptest.NewTLSServer(http.HandlerFunc(forecastFunc(tt.lang, tt.units, tt.resStatus, tt.resMessage)))
+			server := httptest.NewTLSServer(http.HandlerFunc(forecastFunc(tt.lang, tt.units, tt.exclude, tt.extend, tt.resStatus, tt.resMessage)))
 			defer server.Close()
 
 			var err error
@@ -338,7 +647,7 @@ func TestForecast_Send(t *testing.T) {
 			}
 			f.httpClient = server.Client()
 
-			res, err := f.Get(tt.lang, tt.units)
+			res, err := f.Get(tt.lang, tt.units, tt.opts...)
 			if err != nil {
 				if tt.expectedError == nil {
 					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
@@ -348,10 +657,394 @@ func TestForecast_Send(t *testing.T) {
 			} else {
 				if tt.expectedError != nil {
 					t.Errorf("It was expected that an error occurred, but it did not occur")
-				} else if !reflect.DeepEqual(res, tt.expectedResponse) {
+				} else if !sameDeep(res, tt.expectedResponse) {
 					t.Errorf("Expected to get [%+v], but got [%+v]", tt.expectedResponse, res)
 				}
 			}
 		})
 	}
 }
+
+func TestForecast_Get_baseURL(t *testing.T) {
+	tests := []struct {
+		prefix string
+	}{
+		// TEST0 {{{
+		{
+			prefix: "",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			prefix: "/darksky",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			body := readFile("testdata/forecast/get00.json")
+			path := tt.prefix + "/forecast/abcde/35.6895,139.6917"
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != path {
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprintf(w, "Unexpected path: %s", r.URL.Path)
+					return
+				}
+
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			f, ok := NewForecast("abcde", "35.6895", "139.6917", WithBaseURL(server.URL+tt.prefix)).(*forecast)
+			if !ok {
+				t.Fatal("Expected forecast instance, but not.")
+			}
+			f.httpClient = server.Client()
+
+			res, err := f.Get(LangJa, UnitsSI)
+			if err != nil {
+				t.Fatalf("Expected no error occurred, but it occurred (%v)", err)
+			}
+
+			expected := reportIn(unmarshal(body).Report(), UnitsSI)
+			if !sameDeep(res, expected) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", expected, res)
+			}
+		})
+	}
+}
+
+// missingConditions : API から値が得られなかった気象要素
+func missingConditions() *Conditions {
+	return &Conditions{
+		PrecipIntensity: math.NaN(),
+		DewPoint:        math.NaN(),
+		Humidity:        math.NaN(),
+		Pressure:        math.NaN(),
+		WindSpeed:       math.NaN(),
+		WindGust:        math.NaN(),
+		WindBearing:     math.NaN(),
+		CloudCover:      math.NaN(),
+		UVIndex:         math.NaN(),
+		Visibility:      math.NaN(),
+		Ozone:           math.NaN(),
+	}
+}
+
+func TestForecastResponse_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		response ForecastResponse
+	}{
+		// TEST0 {{{
+		{
+			response: *unmarshal(readFile("testdata/forecast/get00.json")),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			response: *unmarshal(readFile("testdata/forecast/get03.json")),
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			response: ForecastResponse{
+				TimeZone: NewTimeZone(loadLocation("Asia/Tokyo")),
+				Currently: &DataPoint{
+					Time:        time.Unix(1517410920, 0),
+					Weather:     WeatherClearDay,
+					Temperature: 2.5,
+					WindSpeed:   2.1,
+					UVIndex:     math.NaN(),
+				},
+				Alerts: []AlertObject{
+					{Title: "乾燥注意報", Time: time.Unix(1517385600, 0)},
+				},
+				Flags: Flags{Units: UnitsUS},
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			b, err := json.Marshal(tt.response)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual ForecastResponse
+			if err := json.Unmarshal(b, &actual); err != nil {
+				t.Fatal(err)
+			}
+
+			if actual.Location().String() != tt.response.Location().String() {
+				t.Errorf("Expected location is %s, but it's %s", tt.response.Location(), actual.Location())
+			}
+			actual.TimeZone = tt.response.TimeZone
+			if !sameDeep(actual, tt.response) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.response, actual)
+			}
+		})
+	}
+}
+
+func TestForecastResponse_Report(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		json string
+
+		expected *Report
+	}{
+		// TEST0 {{{
+		{
+			json: `{"timezone":"Asia/Tokyo"}`,
+
+			expected: &Report{
+				Location: tokyo,
+			},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			json: `{
+				"timezone":"Asia/Tokyo",
+				"hourly":{"data":[
+					{"time":1517410800,"summary":"晴れ","icon":"clear-day","precipProbability":0.02,"temperature":2.61,"apparentTemperature":-0.56}
+				]},
+				"daily":{"data":[
+					{"time":1517410800,"summary":"雪","icon":"snow","precipProbability":0.17,"precipAccumulation":0.274,
+					 "temperatureHigh":5.84,"temperatureHighTime":1517464800,"temperatureLow":-1.17,"temperatureLowTime":1517518800,
+					 "apparentTemperatureHigh":1.99,"apparentTemperatureHighTime":1517464800,"apparentTemperatureLow":-6.71,"apparentTemperatureLowTime":1517518800}
+				]}
+			}`,
+
+			expected: &Report{
+				Location: tokyo,
+				Hourly: []HourlyPoint{
+					{
+						Time:                time.Unix(1517410800, 0).In(tokyo),
+						Weather:             WeatherClearDay,
+						Summary:             "晴れ",
+						Temperature:         2.61,
+						ApparentTemperature: -0.56,
+						PrecipProbability:   0.02,
+						PrecipAccumulation:  math.NaN(),
+						Conditions:          missingConditions(),
+					},
+				},
+				Daily: []DailyPoint{
+					{
+						Time:                        time.Unix(1517410800, 0).In(tokyo),
+						Weather:                     WeatherSnow,
+						Summary:                     "雪",
+						TemperatureHigh:             5.84,
+						TemperatureHighTime:         time.Unix(1517464800, 0).In(tokyo),
+						TemperatureLow:              -1.17,
+						TemperatureLowTime:          time.Unix(1517518800, 0).In(tokyo),
+						ApparentTemperatureHigh:     1.99,
+						ApparentTemperatureHighTime: time.Unix(1517464800, 0).In(tokyo),
+						ApparentTemperatureLow:      -6.71,
+						ApparentTemperatureLowTime:  time.Unix(1517518800, 0).In(tokyo),
+						PrecipProbability:           0.17,
+						PrecipAccumulation:          0.274,
+						Conditions: &DailyConditions{
+							Conditions:         *missingConditions(),
+							PrecipIntensityMax: math.NaN(),
+							MoonPhase:          math.NaN(),
+						},
+					},
+				},
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			json: readFile("testdata/forecast/get00.json"),
+
+			expected: &Report{
+				Location: tokyo,
+				Hourly: []HourlyPoint{
+					{
+						Time:                time.Unix(1516870800, 0).In(tokyo),
+						Weather:             WeatherWind,
+						Summary:             "弱い風及び薄曇り",
+						Temperature:         1.16,
+						ApparentTemperature: -5.76,
+						PrecipProbability:   0.04,
+						PrecipAccumulation:  0.01,
+						Conditions: &Conditions{
+							PrecipIntensity: 0.0152,
+							PrecipType:      "snow",
+							DewPoint:        -5.97,
+							Humidity:        0.59,
+							Pressure:        1015.71,
+							WindSpeed:       10.85,
+							WindGust:        12.96,
+							WindBearing:     329,
+							CloudCover:      0.39,
+							UVIndex:         0,
+							Visibility:      10.01,
+							Ozone:           300.08,
+						},
+					},
+				},
+				Daily: []DailyPoint{
+					{
+						Time:                        time.Unix(1516806000, 0).In(tokyo),
+						Weather:                     WeatherWind,
+						Summary:                     "弱い風夕方 まで及び霧から朝にかけて。",
+						TemperatureHigh:             2.43,
+						TemperatureHighTime:         time.Unix(1516852800, 0).In(tokyo),
+						TemperatureLow:              -1.8,
+						TemperatureLowTime:          time.Unix(1516888800, 0).In(tokyo),
+						ApparentTemperatureHigh:     -4.15,
+						ApparentTemperatureHighTime: time.Unix(1516852800, 0).In(tokyo),
+						ApparentTemperatureLow:      -6.76,
+						ApparentTemperatureLowTime:  time.Unix(1516888800, 0).In(tokyo),
+						PrecipProbability:           0.43,
+						PrecipAccumulation:          1.704,
+						Conditions: &DailyConditions{
+							Conditions: Conditions{
+								PrecipIntensity: 0.0864,
+								PrecipType:      "snow",
+								DewPoint:        -4.47,
+								Humidity:        0.72,
+								Pressure:        1015.63,
+								WindSpeed:       8.66,
+								WindGust:        15.91,
+								WindBearing:     318,
+								CloudCover:      0.46,
+								UVIndex:         3,
+								Visibility:      8.29,
+								Ozone:           310.87,
+							},
+							SunriseTime:        time.Unix(1516831011, 0).In(tokyo),
+							SunsetTime:         time.Unix(1516868037, 0).In(tokyo),
+							MoonPhase:          0.26,
+							PrecipIntensityMax: 0.2261,
+						},
+					},
+				},
+			},
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			json: `{
+				"timezone":"Asia/Tokyo",
+				"currently":{"time":1517410920,"summary":"晴れ","icon":"clear-day","precipProbability":0,"temperature":2.5,"apparentTemperature":-0.7,"windSpeed":2.1},
+				"minutely":{"summary":"1時間晴れ。","icon":"clear-day","data":[
+					{"time":1517410920,"precipIntensity":0,"precipProbability":0},
+					{"time":1517410980,"precipIntensity":0.05,"precipProbability":0.2,"precipType":"rain"}
+				]},
+				"alerts":[
+					{"title":"乾燥注意報","regions":["東京地方"],"severity":"advisory","time":1517385600,"description":"空気の乾燥した状態が続きます。","uri":"https://www.jma.go.jp/"}
+				]
+			}`,
+
+			expected: &Report{
+				Location: tokyo,
+				Currently: &HourlyPoint{
+					Time:                time.Unix(1517410920, 0).In(tokyo),
+					Weather:             WeatherClearDay,
+					Summary:             "晴れ",
+					Temperature:         2.5,
+					ApparentTemperature: -0.7,
+					PrecipAccumulation:  math.NaN(),
+					Conditions: func() *Conditions {
+						c := missingConditions()
+						c.WindSpeed = 2.1
+						return c
+					}(),
+				},
+				Minutely: []MinutelyPoint{
+					{Time: time.Unix(1517410920, 0).In(tokyo)},
+					{Time: time.Unix(1517410980, 0).In(tokyo), PrecipIntensity: 0.05, PrecipProbability: 0.2, PrecipType: "rain"},
+				},
+				MinutelySummary: "1時間晴れ。",
+				Alerts: []Alert{
+					{
+						Title:       "乾燥注意報",
+						Severity:    "advisory",
+						Regions:     []string{"東京地方"},
+						Time:        time.Unix(1517385600, 0).In(tokyo),
+						Description: "空気の乾燥した状態が続きます。",
+						URI:         "https://www.jma.go.jp/",
+					},
+				},
+			},
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			json: `{
+				"timezone":"Asia/Tokyo",
+				"hourly":{"data":[
+					{"time":1517410800,"summary":"晴れ","icon":"clear-day"}
+				]}
+			}`,
+
+			expected: &Report{
+				Location: tokyo,
+				Hourly: []HourlyPoint{
+					{
+						Time:                time.Unix(1517410800, 0).In(tokyo),
+						Weather:             WeatherClearDay,
+						Summary:             "晴れ",
+						Temperature:         math.NaN(),
+						ApparentTemperature: math.NaN(),
+						PrecipProbability:   math.NaN(),
+						PrecipAccumulation:  math.NaN(),
+						Conditions:          missingConditions(),
+					},
+				},
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := unmarshal(tt.json).Report()
+			if len(actual.Hourly) > len(tt.expected.Hourly) {
+				actual.Hourly = actual.Hourly[:len(tt.expected.Hourly)]
+			}
+			if len(actual.Daily) > len(tt.expected.Daily) {
+				actual.Daily = actual.Daily[:len(tt.expected.Daily)]
+			}
+			if actual.Location.String() != tt.expected.Location.String() {
+				t.Errorf("Expected location is %s, but it's %s", tt.expected.Location, actual.Location)
+			}
+			if !sameDeep(actual.Hourly, tt.expected.Hourly) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected.Hourly, actual.Hourly)
+			}
+			if !sameDeep(actual.Daily, tt.expected.Daily) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected.Daily, actual.Daily)
+			}
+			if !sameDeep(actual.Currently, tt.expected.Currently) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected.Currently, actual.Currently)
+			}
+			if !sameDeep(actual.Minutely, tt.expected.Minutely) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected.Minutely, actual.Minutely)
+			}
+			if actual.MinutelySummary != tt.expected.MinutelySummary {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected.MinutelySummary, actual.MinutelySummary)
+			}
+			if !reflect.DeepEqual(actual.Alerts, tt.expected.Alerts) {
+				t.Errorf("Expected to get [%+v], but got [%+v]", tt.expected.Alerts, actual.Alerts)
+			}
+		})
+	}
+}
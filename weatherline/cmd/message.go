@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/yyotti/weatherline"
+)
+
+// defaultMessageTemplate : テンプレートを指定しない場合のメッセージ
+const defaultMessageTemplate = `
+{{with .Alerts}}{{range .}}{{alert .}}
+{{end}}
+{{end}}{{.Date.Format "01/02"}}
+{{with .Now}}  Now {{weather .Weather .Summary}} {{$.Temp .Temperature}}/{{$.Temp .ApparentTemperature}}
+{{end}}{{with .NextHour}}  Next hour: {{.Summary}} {{$.Format.Percent .PrecipProbability}}/{{$.Format.Intensity .PrecipIntensity}}
+{{end}}{{with .Hours}}{{range .}}  {{.Time.Format "15:04"}} {{weather .Weather .Summary}} {{$.Temp .Temperature}}/{{$.Temp .ApparentTemperature}} {{$.Precip .Weather .PrecipProbability .PrecipAccumulation}}
+{{end}}
+{{end}}{{range .Days}}{{.Time.Format "01/02"}} {{icon .Weather}}  {{$.Precip .Weather .PrecipProbability .PrecipAccumulation}}
+  {{$.Temp .TemperatureHigh}}/{{$.Temp .ApparentTemperatureHigh}}({{time "15:04" .ApparentTemperatureHighTime}})
+  {{$.Temp .TemperatureLow}}/{{$.Temp .ApparentTemperatureLow}}({{time "15:04" .ApparentTemperatureLowTime}})
+
+{{end}}`
+
+// alertMessageTemplate : 気象警報だけを通知する場合のメッセージ
+const alertMessageTemplate = `
+{{range .Alerts}}⚠ {{.Title}}{{with .Severity}} ({{.}}){{end}}
+{{with .Regions}}{{join . ", "}}
+{{end}}{{time "01/02 15:04" .Time}} - {{time "01/02 15:04" .Expires}}
+{{with .Description}}{{.}}
+{{end}}{{with .URI}}{{.}}
+{{end}}
+{{end}}`
+
+var messageFuncs = template.FuncMap{
+	"value":   weatherline.FormatValue,
+	"time":    weatherline.FormatTime,
+	"icon":    weatherline.IconText,
+	"weather": weatherline.WeatherText,
+	"alert":   weatherline.AlertText,
+	"join":    strings.Join,
+}
+
+var alertTemplate = template.Must(template.New("alert").Funcs(messageFuncs).Parse(alertMessageTemplate))
+
+// MessageUnits : メッセージに表示する単位 (予報値の単位系で決まる)
+type MessageUnits struct {
+	Temperature  string
+	Accumulation string
+	Intensity    string
+	Speed        string
+	Distance     string
+}
+
+// NextHour : 1時間先までの降水の見通し
+type NextHour struct {
+	Summary           string
+	PrecipProbability float64 // 最大の降水確率 (0-1)
+	PrecipIntensity   float64 // 最大の降水強度
+}
+
+// MessageView : メッセージテンプレートに渡す値
+type MessageView struct {
+	Date     time.Time                 // 予報の対象日 (予報地点のタイムゾーン)
+	Now      *weatherline.HourlyPoint  // 現在の天気 (対象日が今日で currently を取得した場合のみ)
+	NextHour *NextHour                 // 1時間先までの降水 (対象日が今日で minutely を取得した場合のみ)
+	Alerts   []weatherline.Alert       // 対象日に有効な気象警報
+	Hours    []weatherline.HourlyPoint // 対象日の時間別予報
+	Days     []weatherline.DailyPoint  // 対象日の翌日から dateRange 日分の日別予報
+	Units    MessageUnits
+
+	// 気象要素 (Conditions) の書式は {{$.Format.Speed .Conditions.WindSpeed}} のように使う
+	Format weatherline.Formatter
+}
+
+// newMessageView : 予報からテンプレートに渡す値を作る
+func newMessageView(date time.Time, f *weatherline.Report) *MessageView {
+	date = truncHour(date.In(f.Location))
+
+	format := weatherline.NewFormatter(f.Units)
+	v := &MessageView{
+		Date: date,
+		Units: MessageUnits{
+			Temperature:  format.TemperatureSymbol(),
+			Accumulation: format.AccumulationSymbol(),
+			Intensity:    format.IntensitySymbol(),
+			Speed:        format.SpeedSymbol(),
+			Distance:     format.DistanceSymbol(),
+		},
+		Format: format,
+		Alerts: activeAlerts(date, f),
+	}
+
+	if f.Currently != nil && truncHour(f.Currently.Time).Equal(date) {
+		v.Now = f.Currently
+	}
+
+	if len(f.Minutely) > 0 && truncHour(f.Minutely[0].Time).Equal(date) {
+		v.NextHour = newNextHour(f.MinutelySummary, f.Minutely)
+	}
+
+	for _, point := range f.Hourly {
+		if truncHour(point.Time).Equal(date) {
+			v.Hours = append(v.Hours, point)
+		}
+	}
+
+	to := date.AddDate(0, 0, dateRange)
+	for _, point := range f.Daily {
+		d := truncHour(point.Time)
+		if d.After(date) && !d.After(to) {
+			v.Days = append(v.Days, point)
+		}
+	}
+
+	return v
+}
+
+// activeAlerts : 対象日に有効な気象警報
+func activeAlerts(date time.Time, f *weatherline.Report) []weatherline.Alert {
+	date = truncHour(date.In(f.Location))
+
+	var alerts []weatherline.Alert
+	for _, a := range f.Alerts {
+		if a.ActiveIn(date, date.AddDate(0, 0, 1)) {
+			alerts = append(alerts, a)
+		}
+	}
+
+	return alerts
+}
+
+// missingSections : 予報が得られなかった項目 (対象日の時間別予報と、翌日から dateRange 日分の日別予報)
+func missingSections(v *MessageView) []string {
+	var missing []string
+	if len(v.Hours) == 0 {
+		missing = append(missing, fmt.Sprintf("hourly forecast on %s", v.Date.Format("01/02")))
+	}
+
+	for i := 1; i <= dateRange; i++ {
+		d := v.Date.AddDate(0, 0, i)
+
+		found := false
+		for _, p := range v.Days {
+			if truncHour(p.Time).Equal(d) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, fmt.Sprintf("daily forecast on %s", d.Format("01/02")))
+		}
+	}
+
+	return missing
+}
+
+// newNextHour : 1分ごとの予報から1時間先までの降水の見通しを作る
+func newNextHour(summary string, points []weatherline.MinutelyPoint) *NextHour {
+	n := &NextHour{
+		Summary:           summary,
+		PrecipProbability: math.NaN(),
+		PrecipIntensity:   math.NaN(),
+	}
+	for _, p := range points {
+		if !math.IsNaN(p.PrecipProbability) && (math.IsNaN(n.PrecipProbability) || p.PrecipProbability > n.PrecipProbability) {
+			n.PrecipProbability = p.PrecipProbability
+		}
+		if !math.IsNaN(p.PrecipIntensity) && (math.IsNaN(n.PrecipIntensity) || p.PrecipIntensity > n.PrecipIntensity) {
+			n.PrecipIntensity = p.PrecipIntensity
+		}
+	}
+
+	return n
+}
+
+// Temp : 単位付きの気温 (値が得られない場合は "-")
+func (v *MessageView) Temp(t float64) string {
+	return v.Format.Temperature(t)
+}
+
+// Precip : 降水確率 (雪の場合は積雪量も)
+func (v *MessageView) Precip(weather weatherline.Weather, probability, accumulation float64) string {
+	return v.Format.Precip(weather, probability, accumulation)
+}
+
+// parseMessageTemplate : メッセージのテンプレートを読み込む
+//
+// file が指定されていればそのファイルの内容を、そうでなければ text をテンプレートにする。
+// どちらも空の場合は defaultMessageTemplate を使う。
+func parseMessageTemplate(text, file string) (*template.Template, error) {
+	if file != "" {
+		b, err := ioutil.ReadFile(expandHome(file))
+		if err != nil {
+			return nil, err
+		}
+		text = string(b)
+	} else if text == "" {
+		text = defaultMessageTemplate
+	}
+
+	return template.New("message").Funcs(messageFuncs).Parse(text)
+}
+
+// expandHome : 先頭の ~ をホームディレクトリに展開する
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, path[1:])
+}
+
+func createMessage(t *template.Template, date time.Time, f *weatherline.Report) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, newMessageView(date, f)); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// createAlertMessage : 気象警報だけを通知するメッセージ (対象日に有効な気象警報がなければ nil)
+func createAlertMessage(date time.Time, f *weatherline.Report) (*weatherline.Message, error) {
+	v := newMessageView(date, f)
+	if len(v.Alerts) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := alertTemplate.Execute(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return &weatherline.Message{
+		Date:   v.Date,
+		Text:   buf.String(),
+		Alerts: v.Alerts,
+	}, nil
+}
@@ -0,0 +1,445 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yyotti/weatherline"
+)
+
+func TestParseMessageTemplate(t *testing.T) {
+	tests := []struct {
+		text string
+		file string
+
+		expected    string
+		expectedErr bool
+	}{
+		// TEST0 {{{
+		{
+			text: "",
+
+			expected: "\n01/31\n",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			text: `{{.Date.Format "Jan 2"}}`,
+
+			expected: "Jan 31",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			file: "../../testdata/weatherline/cmd/message.tmpl",
+
+			expected: "2018-01-31",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			text: `{{.Date.Format "Jan 2"`,
+
+			expectedErr: true,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			text: `{{unknown .Date}}`,
+
+			expectedErr: true,
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			text: `{{.Date.Format "Jan 2"}}`,
+			file: "../../testdata/weatherline/cmd/message.tmpl",
+
+			expected: "2018-01-31",
+		},
+		// }}}
+		// TEST6 {{{
+		{
+			file: "../../testdata/weatherline/cmd/unknown.tmpl",
+
+			expectedErr: true,
+		},
+		// }}}
+		// TEST7 {{{
+		{
+			text: "../../testdata/weatherline/cmd/message.tmpl",
+
+			expected: "../../testdata/weatherline/cmd/message.tmpl",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := parseMessageTemplate(tt.text, tt.file)
+			if err != nil {
+				if !tt.expectedErr {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				}
+				return
+			} else if tt.expectedErr {
+				t.Fatal("It was expected that an error occurred, but it did not occur")
+			}
+
+			actual, err := createMessage(tmpl, time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC), &weatherline.Report{Location: time.UTC})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		path string
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			path: "~/message.tmpl",
+
+			expected: filepath.Join(home, "message.tmpl"),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			path: "~",
+
+			expected: home,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			path: "/tmp/message.tmpl",
+
+			expected: "/tmp/message.tmpl",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			path: "~user/message.tmpl",
+
+			expected: "~user/message.tmpl",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := expandHome(tt.path)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func reportIn(r *weatherline.Report, units weatherline.Units) *weatherline.Report {
+	r.Units = units
+	return r
+}
+
+func TestCreateMessage(t *testing.T) {
+	tests := []struct {
+		template string
+		date     time.Time
+		report   *weatherline.Report
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			date:   time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC),
+			report: loadReport("../../testdata/weatherline/cmd/run.json"),
+
+			expected: readFile("../../testdata/weatherline/cmd/run00.txt"),
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			date:   time.Date(2018, 2, 5, 0, 0, 0, 0, time.UTC),
+			report: loadReport("../../testdata/weatherline/cmd/run.json"),
+
+			expected: readFile("../../testdata/weatherline/cmd/run01.txt"),
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			template: `{{range $i, $h := .Hours}}{{if lt $i 2}}{{$h.Time.Format "15"}}h {{icon $h.Weather}} {{$.Temp $h.Temperature}}
+{{end}}{{end}}{{range .Days}}{{.Time.Format "Mon"}} {{value "%.0f" .TemperatureHigh}}{{$.Units.Temperature}}
+{{end}}`,
+			date:   time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC),
+			report: loadReport("../../testdata/weatherline/cmd/run.json"),
+
+			expected: "00h ☀ 2.1℃\n01h ☀ 1.9℃\nThu 9℃\nFri 9℃\nSat 10℃\n",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			template: `{{range .Days}}{{.Time.Format "01/02"}} {{$.Temp .TemperatureHigh}} ({{$.Units.Accumulation}})
+{{end}}`,
+			date:   time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC),
+			report: reportIn(loadReport("../../testdata/weatherline/cmd/run.json"), weatherline.UnitsUS),
+
+			expected: "02/01 9.2℉ (in)\n02/02 9.2℉ (in)\n02/03 10.2℉ (in)\n",
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			template: `{{range $i, $h := .Hours}}{{if lt $i 2}}{{with .Conditions}}{{$.Format.Speed .WindSpeed}} {{$.Format.Bearing .WindBearing}} {{$.Format.Percent .Humidity}} {{$.Format.Pressure .Pressure}}{{end}}
+{{end}}{{end}}{{range .Days}}{{with .Conditions}}{{.SunriseTime.Format "15:04"}}-{{.SunsetTime.Format "15:04"}} UV{{.UVIndex}}{{end}}
+{{end}}`,
+			date:   time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC),
+			report: reportIn(loadReport("../../testdata/weatherline/cmd/run.json"), weatherline.UnitsSI),
+
+			expected: "4.1m/s WNW 63% 1024hPa\n4.0m/s NW 64% 1024hPa\n06:52-17:21 UV2\n06:51-17:22 UV2\n06:50-17:23 UV4\n",
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			date:   time.Date(2018, 1, 25, 0, 0, 0, 0, time.UTC),
+			report: loadReport("../../testdata/forecast/get03.json"),
+
+			expected: readFile("../../testdata/weatherline/cmd/run02.txt"),
+		},
+		// }}}
+		// TEST6 {{{
+		{
+			template: `{{with .Now}}{{.Time.Format "15:04"}}{{end}}/{{with .NextHour}}{{.Summary}}{{end}}`,
+			date:     time.Date(2018, 1, 26, 0, 0, 0, 0, time.UTC),
+			report:   loadReport("../../testdata/forecast/get03.json"),
+
+			expected: "/",
+		},
+		// }}}
+		// TEST7 {{{
+		{
+			date: time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC),
+			report: &weatherline.Report{
+				Location: time.UTC,
+				Units:    weatherline.UnitsSI,
+				Hourly: []weatherline.HourlyPoint{
+					{
+						Time:                time.Date(2018, 1, 31, 9, 0, 0, 0, time.UTC),
+						Summary:             "Thunderstorm",
+						Temperature:         math.NaN(),
+						ApparentTemperature: math.NaN(),
+						PrecipProbability:   math.NaN(),
+						PrecipAccumulation:  math.NaN(),
+					},
+				},
+				Daily: []weatherline.DailyPoint{
+					{
+						Time:                    time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC),
+						TemperatureHigh:         8,
+						ApparentTemperatureHigh: 6,
+						TemperatureLow:          1,
+						ApparentTemperatureLow:  -1,
+						PrecipProbability:       0.2,
+						PrecipAccumulation:      math.NaN(),
+					},
+				},
+			},
+
+			expected: "\n01/31\n  09:00 Thunderstorm -/- -\n\n02/01 ❓  20%\n  8.0℃/6.0℃(-)\n  1.0℃/-1.0℃(-)\n\n",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := parseMessageTemplate(tt.template, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := createMessage(tmpl, tt.date, tt.report)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCreateAlertMessage(t *testing.T) {
+	tests := []struct {
+		date time.Time
+
+		expectedNil   bool
+		expectedTitle string
+		expectedText  string
+	}{
+		// TEST0 {{{
+		{
+			date: time.Date(2018, 1, 25, 0, 0, 0, 0, time.UTC),
+
+			expectedTitle: "Weather alert 01/25 (Thu)",
+			expectedText:  "\n⚠ 大雪警報 (warning)\n愛知県西部\n01/25 16:00 - 01/26 09:00\n愛知県西部では、26日朝まで大雪に警戒してください。\nhttps://www.jma.go.jp/jp/warn/\n\n",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			date: time.Date(2018, 1, 26, 0, 0, 0, 0, time.UTC),
+
+			expectedTitle: "Weather alert 01/26 (Fri)",
+			expectedText:  "\n⚠ 大雪警報 (warning)\n愛知県西部\n01/25 16:00 - 01/26 09:00\n愛知県西部では、26日朝まで大雪に警戒してください。\nhttps://www.jma.go.jp/jp/warn/\n\n",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			date: time.Date(2018, 1, 27, 0, 0, 0, 0, time.UTC),
+
+			expectedNil: true,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			msg, err := createAlertMessage(tt.date, loadReport("../../testdata/forecast/get03.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.expectedNil {
+				if msg != nil {
+					t.Errorf("Expected nil, but got [%+v]", msg)
+				}
+				return
+			}
+			if msg == nil {
+				t.Fatal("function returns nil")
+			}
+
+			if actual := msg.Title(); actual != tt.expectedTitle {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expectedTitle, actual)
+			}
+			if msg.Text != tt.expectedText {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expectedText, msg.Text)
+			}
+			if len(msg.Alerts) != 1 {
+				t.Errorf("Expected to get 1 alert, but got [%+v]", msg.Alerts)
+			}
+		})
+	}
+}
+
+func TestMissingSections(t *testing.T) {
+	tests := []struct {
+		date time.Time
+
+		expected []string
+	}{
+		// TEST0 {{{
+		{
+			date: time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC),
+
+			expected: nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			date: time.Date(2018, 2, 5, 0, 0, 0, 0, time.UTC),
+
+			expected: []string{"hourly forecast on 02/05", "daily forecast on 02/07", "daily forecast on 02/08"},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := missingSections(newMessageView(tt.date, loadReport("../../testdata/weatherline/cmd/run.json")))
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMessageView_Precip(t *testing.T) {
+	v := &MessageView{Units: MessageUnits{Temperature: "℃", Accumulation: "cm"}}
+
+	tests := []struct {
+		weather      weatherline.Weather
+		probability  float64
+		accumulation float64
+
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			weather:      weatherline.WeatherRain,
+			probability:  0.42,
+			accumulation: 3,
+
+			expected: "42%",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			weather:      weatherline.WeatherSnow,
+			probability:  0.8,
+			accumulation: math.NaN(),
+
+			expected: "80%/-",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			weather:      weatherline.WeatherSnow,
+			probability:  0.8,
+			accumulation: 2.4,
+
+			expected: "80%/2cm",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := v.Precip(tt.weather, tt.probability, tt.accumulation)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/yyotti/weatherline"
+)
+
+// notifiers
+const (
+	notifierLineNotify    = "line-notify"
+	notifierLineMessaging = "line-messaging"
+	notifierSlack         = "slack"
+	notifierDiscord       = "discord"
+	notifierEmail         = "email"
+	notifierTelegram      = "telegram"
+	notifierNtfy          = "ntfy"
+	notifierGotify        = "gotify"
+	notifierMatrix        = "matrix"
+	notifierWebhook       = "webhook"
+	notifierStdout        = "stdout"
+	notifierFile          = "file"
+)
+
+type notifier struct {
+	required []string // 必須の設定
+	create   func() weatherline.Notifier
+}
+
+var notifiers = map[string]notifier{
+	notifierStdout: {
+		create: func() weatherline.Notifier {
+			return weatherline.NewWriter(os.Stdout)
+		},
+	},
+	notifierFile: {
+		required: []string{configOutputFile},
+		create: func() weatherline.Notifier {
+			return weatherline.NewFile(viper.GetString(configOutputFile))
+		},
+	},
+	notifierLineNotify: {
+		required: []string{configLineToken},
+		create: func() weatherline.Notifier {
+			return weatherline.NewLineNotify(viper.GetString(configLineToken))
+		},
+	},
+	notifierLineMessaging: {
+		required: []string{configLineChannelToken, configLineTo},
+		create: func() weatherline.Notifier {
+			return weatherline.NewLineMessaging(viper.GetString(configLineChannelToken), viper.GetStringSlice(configLineTo))
+		},
+	},
+	notifierSlack: {
+		required: []string{configSlackWebhookURL},
+		create: func() weatherline.Notifier {
+			return weatherline.NewSlack(viper.GetString(configSlackWebhookURL))
+		},
+	},
+	notifierDiscord: {
+		required: []string{configDiscordWebhookURL},
+		create: func() weatherline.Notifier {
+			return weatherline.NewDiscord(viper.GetString(configDiscordWebhookURL))
+		},
+	},
+	notifierEmail: {
+		required: []string{configSMTPHost, configSMTPFrom, configSMTPTo},
+		create: func() weatherline.Notifier {
+			return weatherline.NewSMTP(
+				viper.GetString(configSMTPHost),
+				viper.GetInt(configSMTPPort),
+				weatherline.SMTPSecurityValueOf(viper.GetString(configSMTPSecurity)),
+				viper.GetString(configSMTPUsername),
+				viper.GetString(configSMTPPassword),
+				viper.GetString(configSMTPFrom),
+				viper.GetStringSlice(configSMTPTo),
+			)
+		},
+	},
+	notifierTelegram: {
+		required: []string{configTelegramToken, configTelegramChatID},
+		create: func() weatherline.Notifier {
+			return weatherline.NewTelegram(viper.GetString(configTelegramToken), viper.GetString(configTelegramChatID))
+		},
+	},
+	notifierNtfy: {
+		required: []string{configNtfyURL, configNtfyTopic},
+		create: func() weatherline.Notifier {
+			return weatherline.NewNtfy(viper.GetString(configNtfyURL), viper.GetString(configNtfyTopic), viper.GetString(configNtfyToken), viper.GetFloat64(configPushThreshold))
+		},
+	},
+	notifierGotify: {
+		required: []string{configGotifyURL, configGotifyToken},
+		create: func() weatherline.Notifier {
+			return weatherline.NewGotify(viper.GetString(configGotifyURL), viper.GetString(configGotifyToken), viper.GetFloat64(configPushThreshold))
+		},
+	},
+	notifierMatrix: {
+		required: []string{configMatrixURL, configMatrixToken, configMatrixRoomID},
+		create: func() weatherline.Notifier {
+			return weatherline.NewMatrix(viper.GetString(configMatrixURL), viper.GetString(configMatrixToken), viper.GetString(configMatrixRoomID))
+		},
+	},
+	notifierWebhook: {
+		required: []string{configWebhookURL},
+		create: func() weatherline.Notifier {
+			// checkConfig でチェック済み
+			header, _ := webhookHeader(viper.GetStringSlice(configWebhookHeaders))
+
+			return weatherline.NewWebhook(
+				viper.GetString(configWebhookMethod),
+				viper.GetString(configWebhookURL),
+				header,
+				viper.GetString(configWebhookTemplate),
+				viper.GetString(configWebhookSecret),
+			)
+		},
+	},
+}
+
+type unknownNotifierError string
+
+func (e unknownNotifierError) Error() string {
+	return fmt.Sprintf("unknown notifier: %s", string(e))
+}
+
+// notifyError : 通知先ごとの結果
+type notifyError struct {
+	sent   []string
+	failed []string
+	errs   map[string]error
+}
+
+func (e notifyError) Error() string {
+	errs := []string{}
+	for _, name := range e.failed {
+		errs = append(errs, fmt.Sprintf("%s: %v", name, e.errs[name]))
+	}
+
+	return fmt.Sprintf("failed to notify [%s] (sent: [%s])", strings.Join(errs, ", "), strings.Join(e.sent, ","))
+}
+
+type invalidHeaderError string
+
+func (e invalidHeaderError) Error() string {
+	return fmt.Sprintf("not a \"Name: value\" header: %s", string(e))
+}
+
+// webhookHeader : "Name: value" 形式の設定をヘッダーにする
+func webhookHeader(headers []string) (http.Header, error) {
+	header := http.Header{}
+	for _, h := range headers {
+		kv := strings.SplitN(h, ":", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, invalidHeaderError(h)
+		}
+
+		header.Add(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+	}
+
+	return header, nil
+}
+
+func notifierNames() []string {
+	if names := viper.GetStringSlice(configNotifiers); len(names) > 0 {
+		return names
+	}
+
+	return []string{notifierLineNotify}
+}
+
+// notifierConfigs : 通知先に必要な設定を返す
+func notifierConfigs() ([]string, error) {
+	required := []string{}
+	for _, name := range notifierNames() {
+		n, ok := notifiers[name]
+		if !ok {
+			return nil, unknownNotifierError(name)
+		}
+
+		for _, r := range n.required {
+			if !contains(required, r) {
+				required = append(required, r)
+			}
+		}
+	}
+
+	return required, nil
+}
+
+// notify : すべての通知先に通知する
+//
+// 失敗した通知先があっても残りの通知先には通知する
+func notify(names []string, targets map[string]weatherline.Notifier, msg *weatherline.Message) error {
+	e := notifyError{
+		errs: map[string]error{},
+	}
+	for _, name := range names {
+		if err := targets[name].Notify(msg); err != nil {
+			e.failed = append(e.failed, name)
+			e.errs[name] = err
+			continue
+		}
+
+		e.sent = append(e.sent, name)
+	}
+
+	if len(e.failed) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+// dryRun : 通知せずに通知先ごとの送信内容を書き出す
+//
+// JSON の送信内容は読みやすいようにインデントする
+func dryRun(w io.Writer, names []string, targets map[string]weatherline.Notifier, msg *weatherline.Message) error {
+	for _, name := range names {
+		b, err := weatherline.Preview(targets[name], msg)
+		if err != nil {
+			return err
+		}
+
+		if json.Valid(b) {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, b, "", "  "); err != nil {
+				return err
+			}
+			b = buf.Bytes()
+		}
+
+		fmt.Fprintf(w, "==> %s <==\n", name)
+		w.Write(b)
+		if !bytes.HasSuffix(b, []byte("\n")) {
+			fmt.Fprintln(w)
+		}
+	}
+
+	return nil
+}
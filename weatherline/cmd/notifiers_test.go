@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/yyotti/weatherline"
+)
+
+func TestNotifyError_Error(t *testing.T) {
+	err := notifyError{
+		sent:   []string{"line-notify"},
+		failed: []string{"slack", "discord"},
+		errs: map[string]error{
+			"slack":   fmt.Errorf("404: no_service"),
+			"discord": fmt.Errorf("401: Unauthorized"),
+		},
+	}
+
+	expected := "failed to notify [slack: 404: no_service, discord: 401: Unauthorized] (sent: [line-notify])"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestNotifierNames(t *testing.T) {
+	tests := []struct {
+		notifiers interface{}
+		expected  []string
+	}{
+		// TEST0 {{{
+		{
+			notifiers: nil,
+			expected:  []string{notifierLineNotify},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			notifiers: []string{"line-notify", "slack"},
+			expected:  []string{"line-notify", "slack"},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			viper.Reset()
+			viper.Set(configNotifiers, tt.notifiers)
+
+			actual := notifierNames()
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWebhookHeader(t *testing.T) {
+	tests := []struct {
+		headers []string
+
+		expected    http.Header
+		expectedErr error
+	}{
+		// TEST0 {{{
+		{
+			headers: nil,
+
+			expected: http.Header{},
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			headers: []string{"Authorization: Bearer XXXXX", "x-api-key:YYYYY", "X-Api-Key: ZZZZZ"},
+
+			expected: http.Header{
+				"Authorization": []string{"Bearer XXXXX"},
+				"X-Api-Key":     []string{"YYYYY", "ZZZZZ"},
+			},
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			headers: []string{"Authorization Bearer XXXXX"},
+
+			expectedErr: invalidHeaderError("Authorization Bearer XXXXX"),
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			headers: []string{": XXXXX"},
+
+			expectedErr: invalidHeaderError(": XXXXX"),
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := webhookHeader(tt.headers)
+			if err != tt.expectedErr {
+				t.Fatalf("Expected to get error [%v], but got [%v]", tt.expectedErr, err)
+			}
+			if err == nil && !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+type stubNotifier struct {
+	err      error
+	messages []*weatherline.Message
+}
+
+func (n *stubNotifier) Notify(msg *weatherline.Message) error {
+	n.messages = append(n.messages, msg)
+	return n.err
+}
+
+func TestNotify(t *testing.T) {
+	tests := []struct {
+		names   []string
+		targets map[string]*stubNotifier
+
+		expected error
+	}{
+		// TEST0 {{{
+		{
+			names: []string{"a", "b"},
+			targets: map[string]*stubNotifier{
+				"a": {},
+				"b": {},
+			},
+
+			expected: nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			names: []string{"a", "b", "c"},
+			targets: map[string]*stubNotifier{
+				"a": {err: errExpected},
+				"b": {},
+				"c": {err: errExpected},
+			},
+
+			expected: notifyError{
+				sent:   []string{"b"},
+				failed: []string{"a", "c"},
+				errs: map[string]error{
+					"a": errExpected,
+					"c": errExpected,
+				},
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			msg := &weatherline.Message{Text: "TEST"}
+
+			targets := map[string]weatherline.Notifier{}
+			for name, n := range tt.targets {
+				targets[name] = n
+			}
+
+			actual := notify(tt.names, targets, msg)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+
+			// 失敗しても残りの通知先に通知する
+			for name, n := range tt.targets {
+				if len(n.messages) != 1 || n.messages[0] != msg {
+					t.Errorf("Expected %s to be notified once, but got %v", name, n.messages)
+				}
+			}
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	msg := &weatherline.Message{Text: "\n01/31\n"}
+
+	a := &stubNotifier{}
+	targets := map[string]weatherline.Notifier{
+		"a":     a,
+		"slack": weatherline.NewSlack("https://hooks.slack.com/services/T000/B000/XXXX"),
+	}
+
+	expected := "==> a <==\n" +
+		"\n01/31\n" +
+		"==> slack <==\n" +
+		"{\n" +
+		"  \"text\": \"01/31\"\n" +
+		"}\n"
+
+	var buf bytes.Buffer
+	if err := dryRun(&buf, []string{"a", "slack"}, targets, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := buf.String()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+
+	// 通知はしない
+	if len(a.messages) != 0 {
+		t.Errorf("Expected not to be notified, but got %v", a.messages)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/yyotti/weatherline"
+)
+
+// providers
+const (
+	providerDarkSky   = "darksky"
+	providerOpenMeteo = "open-meteo"
+	providerJMA       = "jma"
+	providerMETNorway = "met-norway"
+	providerNWS       = "nws"
+	providerConsensus = "consensus"
+)
+
+type provider struct {
+	required []string // 必須の設定
+	create   func() weatherline.Forecast
+}
+
+var providers = map[string]provider{
+	providerDarkSky: {
+		required: []string{configForecastToken, configLatitude, configLongitude},
+		create: func() weatherline.Forecast {
+			opts := []weatherline.ForecastOption{}
+			if u := viper.GetString(configForecastURL); u != "" {
+				opts = append(opts, weatherline.WithBaseURL(u))
+			}
+
+			return weatherline.NewForecast(viper.GetString(configForecastToken), viper.GetString(configLatitude), viper.GetString(configLongitude), opts...)
+		},
+	},
+	providerOpenMeteo: {
+		required: []string{configLatitude, configLongitude},
+		create: func() weatherline.Forecast {
+			return weatherline.NewOpenMeteo(viper.GetString(configLatitude), viper.GetString(configLongitude))
+		},
+	},
+	providerJMA: {
+		required: []string{configJMAArea},
+		create: func() weatherline.Forecast {
+			return weatherline.NewJMA(viper.GetString(configJMAArea))
+		},
+	},
+	providerMETNorway: {
+		required: []string{configLatitude, configLongitude, configTimezone},
+		create: func() weatherline.Forecast {
+			return weatherline.NewMETNorway(viper.GetString(configLatitude), viper.GetString(configLongitude), viper.GetString(configTimezone), userAgent(), weatherline.NewFileCache(xdgDirs.CacheHome()))
+		},
+	},
+	providerNWS: {
+		required: []string{configLatitude, configLongitude},
+		create: func() weatherline.Forecast {
+			return weatherline.NewNWS(viper.GetString(configLatitude), viper.GetString(configLongitude), userAgent(), weatherline.NewFileCache(xdgDirs.CacheHome()))
+		},
+	},
+}
+
+func init() {
+	// 他のプロバイダを参照するため init で登録する
+	providers[providerConsensus] = provider{
+		required: []string{configConsensusProviders},
+		create: func() weatherline.Forecast {
+			forecasts := map[string]weatherline.Forecast{}
+			for _, name := range viper.GetStringSlice(configConsensusProviders) {
+				forecasts[name] = providers[name].create()
+			}
+
+			return weatherline.NewConsensus(forecasts)
+		},
+	}
+}
+
+// requiredConfigs : プロバイダに必要な設定を返す
+//
+// consensus の場合はまとめるプロバイダに必要な設定も含める
+func requiredConfigs(name string) ([]string, error) {
+	p, ok := providers[name]
+	if !ok {
+		return nil, unknownProviderError(name)
+	}
+
+	if name != providerConsensus {
+		return p.required, nil
+	}
+
+	required := append([]string{}, p.required...)
+	for _, n := range viper.GetStringSlice(configConsensusProviders) {
+		sub, ok := providers[n]
+		if !ok || n == providerConsensus {
+			return nil, unknownProviderError(n)
+		}
+
+		for _, r := range sub.required {
+			if !contains(required, r) {
+				required = append(required, r)
+			}
+		}
+	}
+
+	return required, nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func providerName() string {
+	if name := viper.GetString(configProvider); name != "" {
+		return name
+	}
+
+	return providerDarkSky
+}
+
+func userAgent() string {
+	if ua := viper.GetString(configUserAgent); ua != "" {
+		return ua
+	}
+
+	v := version
+	if v == "" {
+		v = "develop"
+	}
+
+	return fmt.Sprintf("%s/%s github.com/%s/%s", appName, v, vendor, appName)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestProviderName(t *testing.T) {
+	tests := []struct {
+		provider string
+		expected string
+	}{
+		// TEST0 {{{
+		{
+			provider: "",
+			expected: providerDarkSky,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			provider: "jma",
+			expected: providerJMA,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			viper.Reset()
+			viper.Set(configProvider, tt.provider)
+
+			actual := providerName()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		version   string
+		expected  string
+	}{
+		// TEST0 {{{
+		{
+			userAgent: "",
+			version:   "",
+			expected:  "weatherline/develop github.com/yyotti/weatherline",
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			userAgent: "",
+			version:   "1.0.2",
+			expected:  "weatherline/1.0.2 github.com/yyotti/weatherline",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			userAgent: "myapp/1.0 me@example.com",
+			version:   "1.0.2",
+			expected:  "myapp/1.0 me@example.com",
+		},
+		// }}}
+	}
+
+	v := version
+	defer func() {
+		version = v
+	}()
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			viper.Reset()
+			viper.Set(configUserAgent, tt.userAgent)
+			version = tt.version
+
+			actual := userAgent()
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -1,8 +1,9 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -23,12 +24,52 @@ const (
 )
 
 const (
-	configLineToken     = "line-token"
-	configForecastToken = "forecast-token"
-	configLongitude     = "longitude"
-	configLatitude      = "latitude"
-	configLang          = "lang"
-	configUnits         = "units"
+	configLineToken          = "line-token"
+	configLineChannelToken   = "line-channel-token"
+	configLineTo             = "line-to"
+	configTelegramToken      = "telegram-token"
+	configTelegramChatID     = "telegram-chat-id"
+	configSlackWebhookURL    = "slack-webhook-url"
+	configDiscordWebhookURL  = "discord-webhook-url"
+	configSMTPHost           = "smtp-host"
+	configSMTPPort           = "smtp-port"
+	configSMTPSecurity       = "smtp-security"
+	configSMTPUsername       = "smtp-username"
+	configSMTPPassword       = "smtp-password"
+	configSMTPFrom           = "smtp-from"
+	configSMTPTo             = "smtp-to"
+	configNtfyURL            = "ntfy-url"
+	configNtfyTopic          = "ntfy-topic"
+	configNtfyToken          = "ntfy-token"
+	configGotifyURL          = "gotify-url"
+	configGotifyToken        = "gotify-token"
+	configPushThreshold      = "push-threshold"
+	configMatrixURL          = "matrix-url"
+	configMatrixToken        = "matrix-token"
+	configMatrixRoomID       = "matrix-room-id"
+	configWebhookURL         = "webhook-url"
+	configWebhookMethod      = "webhook-method"
+	configWebhookHeaders     = "webhook-headers"
+	configWebhookTemplate    = "webhook-template"
+	configWebhookSecret      = "webhook-secret"
+	configOutputFile         = "output-file"
+	configTemplate           = "template"
+	configTemplateFile       = "template-file"
+	configDryRun             = "dry-run"
+	configSeparateAlerts     = "separate-alerts"
+	configProvider           = "provider"
+	configForecastToken      = "forecast-token"
+	configForecastURL        = "forecast-url"
+	configLongitude          = "longitude"
+	configLatitude           = "latitude"
+	configJMAArea            = "jma-area"
+	configTimezone           = "timezone"
+	configUserAgent          = "user-agent"
+	configConsensusProviders = "consensus-providers"
+	configBlocks             = "blocks"
+	configNotifiers          = "notifiers"
+	configLang               = "lang"
+	configUnits              = "units"
 )
 
 var (
@@ -47,15 +88,15 @@ var (
 
 	today = truncHour(time.Now())
 
-	lineNotify weatherline.LineNotify
-	forecast   weatherline.Forecast
+	targets  map[string]weatherline.Notifier
+	forecast weatherline.Forecast
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:     "weatherline",
 	Short:   "Send weather forecast to LINE",
-	Long:    `Get weather forecast from Forecast (Dark Sky) API and send it by LINE Notify API`,
+	Long:    `Get weather forecast from the configured provider (Forecast (Dark Sky) API by default) and send it to the configured notifiers (LINE Notify API by default)`,
 	Example: strings.Join(examples, "\n"),
 	Version: version,
 	PreRunE: preRun,
@@ -79,14 +120,56 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file")
 
+	rootCmd.PersistentFlags().StringSlice(configNotifiers, []string{notifierLineNotify}, "notifiers to send forecast")
+	rootCmd.PersistentFlags().Bool(configDryRun, false, "print what would be sent to the notifiers instead of sending it")
+	rootCmd.PersistentFlags().Bool(configSeparateAlerts, false, "send severe weather alerts as a separate notification before the forecast")
 	rootCmd.PersistentFlags().StringP(configLineToken, "L", "", "API token for LINE Notify API")
+	rootCmd.PersistentFlags().String(configLineChannelToken, "", "channel access token for LINE Messaging API")
+	rootCmd.PersistentFlags().StringSlice(configLineTo, nil, "user/group IDs to send by LINE Messaging API")
+	rootCmd.PersistentFlags().String(configTelegramToken, "", "bot token for Telegram Bot API")
+	rootCmd.PersistentFlags().String(configTelegramChatID, "", "chat ID (or @channelusername) to send by Telegram Bot API")
+	rootCmd.PersistentFlags().String(configSlackWebhookURL, "", "Slack incoming webhook URL")
+	rootCmd.PersistentFlags().String(configDiscordWebhookURL, "", "Discord webhook URL")
+	rootCmd.PersistentFlags().String(configSMTPHost, "", "SMTP server host")
+	rootCmd.PersistentFlags().Int(configSMTPPort, 587, "SMTP server port")
+	rootCmd.PersistentFlags().String(configSMTPSecurity, weatherline.SMTPSecurityStartTLS.Value(),
+		fmt.Sprintf("SMTP connection security [%s|%s|%s]", weatherline.SMTPSecurityStartTLS.Value(), weatherline.SMTPSecurityTLS.Value(), weatherline.SMTPSecurityNone.Value()))
+	rootCmd.PersistentFlags().String(configSMTPUsername, "", "SMTP username (no authentication if empty)")
+	rootCmd.PersistentFlags().String(configSMTPPassword, "", "SMTP password")
+	rootCmd.PersistentFlags().String(configSMTPFrom, "", "sender address of forecast mail")
+	rootCmd.PersistentFlags().StringSlice(configSMTPTo, nil, "recipient addresses of forecast mail")
+	rootCmd.PersistentFlags().String(configNtfyURL, "https://ntfy.sh", "ntfy server URL")
+	rootCmd.PersistentFlags().String(configNtfyTopic, "", "ntfy topic")
+	rootCmd.PersistentFlags().String(configNtfyToken, "", "ntfy access token (no authentication if empty)")
+	rootCmd.PersistentFlags().String(configGotifyURL, "", "Gotify server URL")
+	rootCmd.PersistentFlags().String(configGotifyToken, "", "Gotify application token")
+	rootCmd.PersistentFlags().Float64(configPushThreshold, weatherline.DefaultPushThreshold, "precipitation probability to raise ntfy/Gotify priority (0-1)")
+	rootCmd.PersistentFlags().String(configMatrixURL, "", "Matrix homeserver URL")
+	rootCmd.PersistentFlags().String(configMatrixToken, "", "access token for Matrix")
+	rootCmd.PersistentFlags().String(configMatrixRoomID, "", "Matrix room ID (e.g. !xxxx:example.org)")
+	rootCmd.PersistentFlags().String(configWebhookURL, "", "webhook URL")
+	rootCmd.PersistentFlags().String(configWebhookMethod, http.MethodPost, "HTTP method of webhook")
+	rootCmd.PersistentFlags().StringSlice(configWebhookHeaders, nil, "HTTP headers of webhook (e.g. \"Authorization: Bearer XXXXX\")")
+	rootCmd.PersistentFlags().String(configWebhookTemplate, "", "text/template of webhook request body (default "+weatherline.DefaultWebhookTemplate+")")
+	rootCmd.PersistentFlags().String(configWebhookSecret, "", "shared secret to sign webhook request body (HMAC-SHA256 in "+weatherline.WebhookSignatureHeader+" header)")
+	rootCmd.PersistentFlags().String(configOutputFile, "", "file to write forecast by file notifier")
+	rootCmd.PersistentFlags().String(configTemplate, "", "text/template of forecast message")
+	rootCmd.PersistentFlags().String(configTemplateFile, "", "file of text/template of forecast message (overrides template)")
+	rootCmd.PersistentFlags().StringP(configProvider, "p", providerDarkSky, "forecast provider")
 	rootCmd.PersistentFlags().StringP(configForecastToken, "F", "", "API token for Forecast (Dark Sky) API")
+	rootCmd.PersistentFlags().String(configForecastURL, "", "base URL of Dark Sky compatible API (e.g. https://api.pirateweather.net)")
 	rootCmd.PersistentFlags().StringP(configLongitude, "x", "", "longitude")
 	rootCmd.PersistentFlags().StringP(configLatitude, "y", "", "latitude")
+	rootCmd.PersistentFlags().String(configJMAArea, "", "area code for JMA forecast (e.g. 130000)")
+	rootCmd.PersistentFlags().String(configTimezone, "", "IANA time zone of the forecast location for MET Norway (e.g. Asia/Tokyo)")
+	rootCmd.PersistentFlags().String(configUserAgent, "", "User-Agent sent to forecast APIs which require it")
+	rootCmd.PersistentFlags().StringSlice(configConsensusProviders, nil, "forecast providers merged by consensus provider (e.g. darksky,open-meteo)")
+	rootCmd.PersistentFlags().StringSlice(configBlocks, nil,
+		fmt.Sprintf("data blocks to get in addition to hourly/daily forecast and alerts [%s|%s]", weatherline.BlockCurrently.Value(), weatherline.BlockMinutely.Value()))
 	rootCmd.PersistentFlags().StringP(configLang, "l", weatherline.LangEn.Value(),
 		fmt.Sprintf("language [%s|%s]", weatherline.LangEn.Value(), weatherline.LangJa.Value()))
 	rootCmd.PersistentFlags().StringP(configUnits, "u", weatherline.UnitsUS.Value(),
-		fmt.Sprintf("language [%s|%s]", weatherline.UnitsUS.Value(), weatherline.UnitsSI.Value()))
+		fmt.Sprintf("units [%s|%s|%s|%s|%s]", weatherline.UnitsUS.Value(), weatherline.UnitsSI.Value(), weatherline.UnitsCA.Value(), weatherline.UnitsUK2.Value(), weatherline.UnitsAuto.Value()))
 
 	if err := viper.BindPFlags(rootCmd.PersistentFlags()); err != nil {
 		panic(err)
@@ -124,6 +207,21 @@ func initConfig() {
 	}
 }
 
+type unknownProviderError string
+
+func (e unknownProviderError) Error() string {
+	return fmt.Sprintf("unknown provider: %s", string(e))
+}
+
+type invalidFlagError struct {
+	name   string
+	reason string
+}
+
+func (e invalidFlagError) Error() string {
+	return fmt.Sprintf(`invalid flag "%s": %s`, e.name, e.reason)
+}
+
 type requiredFlagsNotSetError []string
 
 func (e requiredFlagsNotSetError) Error() string {
@@ -152,16 +250,38 @@ func preRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	lineNotify = weatherline.NewLineNotify(viper.GetString(configLineToken))
-	forecast = weatherline.NewForecast(viper.GetString(configForecastToken), viper.GetString(configLatitude), viper.GetString(configLongitude))
+	targets = map[string]weatherline.Notifier{}
+	for _, name := range notifierNames() {
+		targets[name] = notifiers[name].create()
+	}
+	forecast = providers[providerName()].create()
 
 	return nil
 }
 
 var checkConfig = func() error {
+	required, e := notifierConfigs()
+	if e != nil {
+		return e
+	}
+
+	providerRequired, e := requiredConfigs(providerName())
+	if e != nil {
+		return e
+	}
+	for _, r := range providerRequired {
+		if !contains(required, r) {
+			required = append(required, r)
+		}
+	}
+
 	err := requiredFlagsNotSetError{}
-	for _, f := range []string{"line-token", "forecast-token", "latitude", "longitude"} {
+	for _, f := range required {
 		switch f {
+		case configConsensusProviders, configLineTo, configSMTPTo:
+			if len(viper.GetStringSlice(f)) == 0 {
+				err = append(err, f)
+			}
 		default:
 			if viper.GetString(f) == "" {
 				err = append(err, f)
@@ -173,7 +293,70 @@ var checkConfig = func() error {
 		return err
 	}
 
-	// TODO Check lang/units
+	if u := viper.GetString(configForecastURL); u != "" {
+		if err := weatherline.ValidateBaseURL(u); err != nil {
+			return invalidFlagError{name: configForecastURL, reason: err.Error()}
+		}
+	}
+
+	if tz := viper.GetString(configTimezone); tz != "" {
+		if err := weatherline.ValidateTimezone(tz); err != nil {
+			return invalidFlagError{name: configTimezone, reason: err.Error()}
+		}
+	}
+
+	for _, f := range []string{configNtfyURL, configGotifyURL, configMatrixURL, configWebhookURL} {
+		if u := viper.GetString(f); u != "" {
+			if err := weatherline.ValidateBaseURL(u); err != nil {
+				return invalidFlagError{name: f, reason: err.Error()}
+			}
+		}
+	}
+
+	if p := viper.GetFloat64(configPushThreshold); p < 0 || p > 1 {
+		return invalidFlagError{name: configPushThreshold, reason: fmt.Sprintf("out of range [0, 1]: %v", p)}
+	}
+
+	if _, err := webhookHeader(viper.GetStringSlice(configWebhookHeaders)); err != nil {
+		return invalidFlagError{name: configWebhookHeaders, reason: err.Error()}
+	}
+
+	if err := weatherline.ValidateWebhookTemplate(viper.GetString(configWebhookTemplate)); err != nil {
+		return invalidFlagError{name: configWebhookTemplate, reason: err.Error()}
+	}
+
+	if id := viper.GetString(configTelegramChatID); id != "" {
+		if err := weatherline.ValidateTelegramChatID(id); err != nil {
+			return invalidFlagError{name: configTelegramChatID, reason: err.Error()}
+		}
+	}
+
+	if s := viper.GetString(configSMTPSecurity); s != "" && weatherline.SMTPSecurityValueOf(s) == weatherline.SMTPSecurityUnknown {
+		return invalidFlagError{name: configSMTPSecurity, reason: fmt.Sprintf("unknown security: %s", s)}
+	}
+
+	if viper.GetString(configTemplate) != "" && viper.GetString(configTemplateFile) != "" {
+		return invalidFlagError{name: configTemplateFile, reason: fmt.Sprintf(`cannot be used with "%s"`, configTemplate)}
+	}
+	if _, err := parseMessageTemplate(viper.GetString(configTemplate), viper.GetString(configTemplateFile)); err != nil {
+		name := configTemplate
+		if viper.GetString(configTemplateFile) != "" {
+			name = configTemplateFile
+		}
+		return invalidFlagError{name: name, reason: err.Error()}
+	}
+
+	for _, b := range viper.GetStringSlice(configBlocks) {
+		if weatherline.BlockValueOf(b) == weatherline.BlockUnknown {
+			return invalidFlagError{name: configBlocks, reason: fmt.Sprintf("unknown block: %s", b)}
+		}
+	}
+
+	if u := viper.GetString(configUnits); u != "" && weatherline.UnitsValueOf(u) == weatherline.UnitsUnknown {
+		return invalidFlagError{name: configUnits, reason: fmt.Sprintf("unknown units: %s", u)}
+	}
+
+	// TODO Check lang
 
 	return nil
 }
@@ -203,110 +386,100 @@ func run(cmd *cobra.Command, args []string) error {
 	lang := weatherline.LangValueOf(viper.GetString("lang"))
 	units := weatherline.UnitsValueOf(viper.GetString("units"))
 
-	f, err := forecast.Get(lang, units)
+	opts := []weatherline.GetOption{weatherline.WithBlocks(forecastBlocks()...)}
+	if extendHourly(date, today) {
+		opts = append(opts, weatherline.WithExtendHourly())
+	}
+
+	f, err := forecast.Get(lang, units, opts...)
 	if err != nil {
 		return err
 	}
 
-	tz := time.Location(f.TimeZone)
-	date = truncHour(date.In(&tz))
+	if providerName() == providerConsensus {
+		for _, name := range viper.GetStringSlice(configConsensusProviders) {
+			if err, ok := f.Failures[name]; ok {
+				cmd.Printf("Failed to get forecast from %s: %v\n", name, err)
+			} else if !contains(f.Sources, name) {
+				cmd.Printf("Failed to get forecast from %s\n", name)
+			}
+		}
+	}
 
-	var buf bytes.Buffer
+	t, err := parseMessageTemplate(viper.GetString(configTemplate), viper.GetString(configTemplateFile))
+	if err != nil {
+		return err
+	}
 
-	buf.WriteString("\n")
-	buf.WriteString(date.Format("01/02"))
-	buf.WriteString("\n")
+	// 気象警報を別に通知する場合は予報より先に通知し、予報のメッセージには含めない
+	var alertErr error
+	if viper.GetBool(configSeparateAlerts) {
+		alertMsg, err := createAlertMessage(date, f)
+		if err != nil {
+			return err
+		}
+		if alertMsg != nil {
+			alertErr = deliver(cmd.OutOrStdout(), alertMsg)
+		}
 
-	hourly := createHourly(date, f)
-	if hourly != "" {
-		buf.WriteString(hourly)
-		buf.WriteString("\n")
+		r := *f
+		r.Alerts = nil
+		f = &r
 	}
 
-	daily := createDaily(date, f)
-	if daily != "" {
-		buf.WriteString(daily)
+	for _, section := range missingSections(newMessageView(date, f)) {
+		cmd.Printf("No %s\n", section)
 	}
 
-	return lineNotify.Send(buf.String())
-}
-
-func createHourly(date time.Time, f *weatherline.ForecastResponse) string {
-	if len(f.Hourly.Data) == 0 {
-		return ""
+	text, err := createMessage(t, date, f)
+	if err != nil {
+		return err
 	}
 
-	var buf bytes.Buffer
-	for _, point := range f.Hourly.Data {
-		d := truncHour(point.Time.Time)
-		if !d.Equal(date) {
-			continue
-		}
+	msg := &weatherline.Message{
+		Date:   truncHour(date.In(f.Location)),
+		Days:   dateRange,
+		Text:   text,
+		Report: f,
+		Alerts: activeAlerts(date, f),
+	}
 
-		buf.WriteString("  ")
-		buf.WriteString(point.Time.Format("15:04"))
-		buf.WriteString(" ")
-		ico, ok := icons[point.Weather]
-		if !ok {
-			buf.WriteString(point.Summary)
-		} else {
-			buf.WriteRune(rune(ico))
-		}
-		buf.WriteString(" ")
-		buf.WriteString(fmt.Sprintf("%.1f℃", point.Temperature))
-		buf.WriteString(fmt.Sprintf("/%.1f℃", point.ApparentTemperature))
-		buf.WriteString(" ")
-		buf.WriteString(fmt.Sprintf("%.0f%%", point.PrecipProbability*100))
-		if point.Weather == weatherline.WeatherSnow {
-			buf.WriteString(fmt.Sprintf("/%.0fcm", point.PrecipAccumulation))
-		}
-		buf.WriteString("\n")
+	if err := deliver(cmd.OutOrStdout(), msg); err != nil {
+		return err
 	}
 
-	return buf.String()
+	return alertErr
 }
 
-func createDaily(date time.Time, f *weatherline.ForecastResponse) string {
-	if len(f.Daily.Data) == 0 {
-		return ""
+// deliver : すべての通知先に通知する (dry-run の場合は送信内容を書き出す)
+func deliver(w io.Writer, msg *weatherline.Message) error {
+	if viper.GetBool(configDryRun) {
+		return dryRun(w, notifierNames(), targets, msg)
 	}
 
-	to := date.AddDate(0, 0, dateRange)
-	var buf bytes.Buffer
-	for _, point := range f.Daily.Data {
-		d := truncHour(point.Time.Time)
-		if !d.After(date) || !d.Before(to) && !d.Equal(to) {
-			continue
-		}
+	return notify(notifierNames(), targets, msg)
+}
 
-		buf.WriteString(point.Time.Format("01/02"))
-		buf.WriteString(" ")
-		ico, ok := icons[point.Weather]
-		if !ok {
-			buf.WriteString("??")
-		} else {
-			buf.WriteRune(rune(ico))
-		}
-		buf.WriteString("  ")
-		buf.WriteString(fmt.Sprintf("%.0f%%", point.PrecipProbability*100))
-		if point.Weather == weatherline.WeatherSnow {
-			buf.WriteString(fmt.Sprintf("/%.0fcm", point.PrecipAccumulation))
+// extendHourly : 時間別予報を延長する必要があるかどうか
+//
+// 通常の時間別予報は48時間先までなので、明後日以降の予報には延長した時間別予報を使う。
+func extendHourly(date, today time.Time) bool {
+	d := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, today.Location())
+	return !d.Before(truncHour(today).AddDate(0, 0, 2))
+}
+
+// forecastBlocks : 取得するデータブロック
+//
+// 気象警報と、予報値の単位系を知るための flags は常に取得する。
+func forecastBlocks() []weatherline.Block {
+	blocks := []weatherline.Block{weatherline.BlockAlerts, weatherline.BlockFlags}
+	for _, b := range viper.GetStringSlice(configBlocks) {
+		if block := weatherline.BlockValueOf(b); block != weatherline.BlockAlerts && block != weatherline.BlockFlags {
+			blocks = append(blocks, block)
 		}
-		buf.WriteString("\n")
-		buf.WriteString("  ")
-		buf.WriteString(fmt.Sprintf("%.1f℃", point.TemperatureHigh))
-		buf.WriteString(fmt.Sprintf("/%.1f℃", point.ApparentTemperatureHigh))
-		buf.WriteString(point.ApparentTemperatureHighTime.Format("(15:04)"))
-		buf.WriteString("\n")
-		buf.WriteString("  ")
-		buf.WriteString(fmt.Sprintf("%.1f℃", point.TemperatureLow))
-		buf.WriteString(fmt.Sprintf("/%.1f℃", point.ApparentTemperatureLow))
-		buf.WriteString(point.ApparentTemperatureLowTime.Format("(15:04)"))
-		buf.WriteString("\n")
-		buf.WriteString("\n")
-	}
-
-	return buf.String()
+	}
+
+	return blocks
 }
 
 func truncHour(t time.Time) time.Time {
@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/yyotti/weatherline"
 )
 
 func TestRequiredFlagsNotSetError_Error(t *testing.T) {
@@ -54,6 +57,20 @@ func TestRequiredFlagsNotSetError_Error(t *testing.T) {
 	}
 }
 
+func TestInvalidFlagError_Error(t *testing.T) {
+	err := invalidFlagError{
+		name:   "aaa",
+		reason: "bbb",
+	}
+
+	expected := `invalid flag "aaa": bbb`
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get %s, but got %s", expected, actual)
+	}
+}
+
 func TestCheckArgs(t *testing.T) {
 	tests := []struct {
 		args     []string
@@ -106,14 +123,65 @@ func TestCheckArgs(t *testing.T) {
 	}
 }
 
+func TestExtendHourly(t *testing.T) {
+	today := time.Date(2018, 1, 31, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		date time.Time
+
+		expected bool
+	}{
+		// TEST0 {{{
+		{
+			date: time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC),
+
+			expected: false,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			date: time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC),
+
+			expected: false,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			date: time.Date(2018, 2, 2, 0, 0, 0, 0, time.UTC),
+
+			expected: true,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			date: time.Date(2018, 2, 6, 0, 0, 0, 0, time.UTC),
+
+			expected: true,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			actual := extendHourly(tt.date, today)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%v], but got [%v]", tt.expected, actual)
+			}
+		})
+	}
+}
+
 func TestCheckConfig(t *testing.T) {
 	tests := []struct {
-		flags    map[string]string
+		flags    map[string]interface{}
 		expected error
 	}{
 		// TEST0 {{{
 		{
-			flags: map[string]string{},
+			flags: map[string]interface{}{},
 			expected: requiredFlagsNotSetError([]string{
 				"line-token",
 				"forecast-token",
@@ -124,7 +192,7 @@ func TestCheckConfig(t *testing.T) {
 		// }}}
 		// TEST1 {{{
 		{
-			flags: map[string]string{
+			flags: map[string]interface{}{
 				"line-token":     "",
 				"forecast-token": "XXXXX",
 				"latitude":       "123.45",
@@ -138,15 +206,591 @@ func TestCheckConfig(t *testing.T) {
 		// }}}
 		// TEST2 {{{
 		{
-			flags: map[string]string{
+			flags: map[string]interface{}{
+				"line-token":     "YYYYY",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":       "darksky",
+				"line-token":     "YYYYY",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":   "unknown",
+				"line-token": "YYYYY",
+			},
+			expected: unknownProviderError("unknown"),
+		},
+		// }}}
+		// TEST5 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":   "open-meteo",
+				"line-token": "YYYYY",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"latitude",
+				"longitude",
+			}),
+		},
+		// }}}
+		// TEST6 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":   "jma",
+				"line-token": "YYYYY",
+				"latitude":   "123.45",
+				"longitude":  "67.890",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"jma-area",
+			}),
+		},
+		// }}}
+		// TEST7 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":   "jma",
+				"line-token": "YYYYY",
+				"jma-area":   "130000",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST8 {{{
+		{
+			flags: map[string]interface{}{
+				"line-token":     "YYYYY",
+				"forecast-token": "XXXXX",
+				"forecast-url":   "https://api.pirateweather.net",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST9 {{{
+		{
+			flags: map[string]interface{}{
 				"line-token":     "YYYYY",
 				"forecast-token": "XXXXX",
+				"forecast-url":   "api.pirateweather.net",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "forecast-url",
+				reason: "not an absolute URL: api.pirateweather.net",
+			},
+		},
+		// }}}
+		// TEST10 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":   "consensus",
+				"line-token": "YYYYY",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"consensus-providers",
+			}),
+		},
+		// }}}
+		// TEST11 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":            "consensus",
+				"line-token":          "YYYYY",
+				"consensus-providers": []string{"open-meteo", "jma", "met-norway"},
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"latitude",
+				"longitude",
+				"jma-area",
+				"timezone",
+			}),
+		},
+		// }}}
+		// TEST12 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":            "consensus",
+				"line-token":          "YYYYY",
+				"consensus-providers": []string{"open-meteo", "unknown"},
+			},
+			expected: unknownProviderError("unknown"),
+		},
+		// }}}
+		// TEST13 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":            "consensus",
+				"line-token":          "YYYYY",
+				"consensus-providers": []string{"open-meteo", "jma"},
+				"latitude":            "123.45",
+				"longitude":           "67.890",
+				"jma-area":            "130000",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST14 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"line-notify", "unknown"},
+				"line-token":     "YYYYY",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: unknownNotifierError("unknown"),
+		},
+		// }}}
+		// TEST15 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":          []string{"line-messaging"},
+				"line-channel-token": "YYYYY",
+				"forecast-token":     "XXXXX",
+				"latitude":           "123.45",
+				"longitude":          "67.890",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"line-to",
+			}),
+		},
+		// }}}
+		// TEST16 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":          []string{"line-messaging"},
+				"line-channel-token": "YYYYY",
+				"line-to":            []string{"U0123"},
+				"forecast-token":     "XXXXX",
+				"latitude":           "123.45",
+				"longitude":          "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST17 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"line-notify", "slack"},
+				"line-token":     "YYYYY",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"slack-webhook-url",
+			}),
+		},
+		// }}}
+		// TEST18 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":           []string{"discord"},
+				"discord-webhook-url": "https://discord.com/api/webhooks/000/XXXX",
+				"forecast-token":      "XXXXX",
+				"latitude":            "123.45",
+				"longitude":           "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST19 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"email"},
+				"smtp-host":      "smtp.example.com",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"smtp-from",
+				"smtp-to",
+			}),
+		},
+		// }}}
+		// TEST20 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"email"},
+				"smtp-host":      "smtp.example.com",
+				"smtp-security":  "ssl",
+				"smtp-from":      "weatherline@example.com",
+				"smtp-to":        []string{"you@example.com"},
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "smtp-security",
+				reason: "unknown security: ssl",
+			},
+		},
+		// }}}
+		// TEST21 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"email"},
+				"smtp-host":      "smtp.example.com",
+				"smtp-security":  "tls",
+				"smtp-from":      "weatherline@example.com",
+				"smtp-to":        []string{"you@example.com"},
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST22 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"telegram"},
+				"telegram-token": "123456:ABCDEF",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"telegram-chat-id",
+			}),
+		},
+		// }}}
+		// TEST23 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":        []string{"telegram"},
+				"telegram-token":   "123456:ABCDEF",
+				"telegram-chat-id": "weather",
+				"forecast-token":   "XXXXX",
+				"latitude":         "123.45",
+				"longitude":        "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "telegram-chat-id",
+				reason: "not a chat ID or @channelusername: weather",
+			},
+		},
+		// }}}
+		// TEST24 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":        []string{"telegram"},
+				"telegram-token":   "123456:ABCDEF",
+				"telegram-chat-id": "-1001234567890",
+				"forecast-token":   "XXXXX",
+				"latitude":         "123.45",
+				"longitude":        "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST25 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"ntfy", "gotify"},
+				"ntfy-url":       "https://ntfy.sh",
+				"gotify-url":     "https://gotify.example.com",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"ntfy-topic",
+				"gotify-token",
+			}),
+		},
+		// }}}
+		// TEST26 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"gotify"},
+				"gotify-url":     "gotify.example.com",
+				"gotify-token":   "AXXXXXXXXXXXXXX",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "gotify-url",
+				reason: "not an absolute URL: gotify.example.com",
+			},
+		},
+		// }}}
+		// TEST27 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"ntfy"},
+				"ntfy-url":       "https://ntfy.sh",
+				"ntfy-topic":     "weather",
+				"push-threshold": 1.5,
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "push-threshold",
+				reason: "out of range [0, 1]: 1.5",
+			},
+		},
+		// }}}
+		// TEST28 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"ntfy", "gotify"},
+				"ntfy-url":       "https://ntfy.sh",
+				"ntfy-topic":     "weather",
+				"gotify-url":     "https://gotify.example.com",
+				"gotify-token":   "AXXXXXXXXXXXXXX",
+				"push-threshold": 0.3,
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST29 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"matrix"},
+				"matrix-url":     "https://matrix.example.org",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"matrix-token",
+				"matrix-room-id",
+			}),
+		},
+		// }}}
+		// TEST30 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"matrix"},
+				"matrix-url":     "https://matrix.example.org",
+				"matrix-token":   "syt_XXXX",
+				"matrix-room-id": "!room:example.org",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST31 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":       []string{"webhook"},
+				"webhook-url":     "https://example.com/hooks/weather",
+				"webhook-headers": []string{"Authorization Bearer XXXXX"},
+				"forecast-token":  "XXXXX",
+				"latitude":        "123.45",
+				"longitude":       "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "webhook-headers",
+				reason: `not a "Name: value" header: Authorization Bearer XXXXX`,
+			},
+		},
+		// }}}
+		// TEST32 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":        []string{"webhook"},
+				"webhook-url":      "https://example.com/hooks/weather",
+				"webhook-template": "{{.Text",
+				"forecast-token":   "XXXXX",
+				"latitude":         "123.45",
+				"longitude":        "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "webhook-template",
+				reason: "template: webhook:1: unclosed action",
+			},
+		},
+		// }}}
+		// TEST33 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":        []string{"webhook"},
+				"webhook-url":      "https://example.com/hooks/weather",
+				"webhook-method":   "PUT",
+				"webhook-headers":  []string{"Authorization: Bearer XXXXX"},
+				"webhook-template": `{"state": {{json (trim .Text)}}}`,
+				"webhook-secret":   "shared",
+				"forecast-token":   "XXXXX",
+				"latitude":         "123.45",
+				"longitude":        "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST34 {{{
+		{
+			flags: map[string]interface{}{
+				"notifiers":      []string{"stdout", "file"},
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: requiredFlagsNotSetError([]string{
+				"output-file",
+			}),
+		},
+		// }}}
+		// TEST35 {{{
+		{
+			flags: map[string]interface{}{
+				"line-token":     "XXXXX",
+				"template":       "{{range .Hours}}{{.Time}}",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "template",
+				reason: "template: message:1: unexpected EOF",
+			},
+		},
+		// }}}
+		// TEST36 {{{
+		{
+			flags: map[string]interface{}{
+				"line-token":     "XXXXX",
+				"template-file":  "../../testdata/weatherline/cmd/message.tmpl",
+				"forecast-token": "XXXXX",
 				"latitude":       "123.45",
 				"longitude":      "67.890",
 			},
 			expected: nil,
 		},
 		// }}}
+		// TEST37 {{{
+		{
+			flags: map[string]interface{}{
+				"line-token":     "XXXXX",
+				"units":          "metric",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "units",
+				reason: "unknown units: metric",
+			},
+		},
+		// }}}
+		// TEST38 {{{
+		{
+			flags: map[string]interface{}{
+				"line-token":     "XXXXX",
+				"units":          "uk2",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST39 {{{
+		{
+			flags: map[string]interface{}{
+				"line-token":     "XXXXX",
+				"blocks":         []string{"currently", "minutely"},
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST40 {{{
+		{
+			flags: map[string]interface{}{
+				"line-token":     "XXXXX",
+				"blocks":         []string{"currently", "hourly"},
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: invalidFlagError{name: "blocks", reason: "unknown block: hourly"},
+		},
+		// }}}
+		// TEST41 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":   "met-norway",
+				"line-token": "XXXXX",
+				"latitude":   "123.45",
+				"longitude":  "67.890",
+				"timezone":   "Asia/Tokyo",
+			},
+			expected: nil,
+		},
+		// }}}
+		// TEST42 {{{
+		{
+			flags: map[string]interface{}{
+				"provider":   "met-norway",
+				"line-token": "XXXXX",
+				"latitude":   "123.45",
+				"longitude":  "67.890",
+				"timezone":   "Local",
+			},
+			expected: invalidFlagError{name: "timezone", reason: "timezone is required"},
+		},
+		// }}}
+		// TEST43 {{{
+		{
+			flags: map[string]interface{}{
+				"line-token":     "XXXXX",
+				"template-file":  "../../testdata/weatherline/cmd/unknown.tmpl",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "template-file",
+				reason: "open ../../testdata/weatherline/cmd/unknown.tmpl: no such file or directory",
+			},
+		},
+		// }}}
+		// TEST44 {{{
+		{
+			flags: map[string]interface{}{
+				"line-token":     "XXXXX",
+				"template":       `{{.Date.Format "01/02"}}`,
+				"template-file":  "../../testdata/weatherline/cmd/message.tmpl",
+				"forecast-token": "XXXXX",
+				"latitude":       "123.45",
+				"longitude":      "67.890",
+			},
+			expected: invalidFlagError{
+				name:   "template-file",
+				reason: `cannot be used with "template"`,
+			},
+		},
+		// }}}
 	}
 
 	for i, tt := range tests {
@@ -270,3 +914,26 @@ func TestPreRun(t *testing.T) {
 		})
 	}
 }
+
+func loadReport(path string) *weatherline.Report {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+
+	r := weatherline.ForecastResponse{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		panic(err)
+	}
+
+	return r.Report()
+}
+
+func readFile(path string) string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(b)
+}
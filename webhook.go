@@ -0,0 +1,161 @@
+package weatherline
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+const (
+	// DefaultWebhookTemplate : テンプレートを指定しない場合のリクエストボディ
+	DefaultWebhookTemplate = `{"title": {{json .Title}}, "text": {{json .Text}}}`
+
+	// WebhookSignatureHeader : HMAC-SHA256 の署名を送るヘッダー
+	WebhookSignatureHeader = "X-Weatherline-Signature"
+)
+
+// webhookFuncs : テンプレートで使える関数
+//
+// precip は通知する予報の単位系に合わせたものに実行時に置き換える。
+var webhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"trim":   strings.TrimSpace,
+	"value":  FormatValue,
+	"time":   FormatTime,
+	"icon":   IconText,
+	"precip": NewFormatter(UnitsUnknown).Precip,
+}
+
+type webhookError struct {
+	Status int
+	Body   string
+}
+
+func (e webhookError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Status, e.Body)
+}
+
+type webhook struct {
+	method   string
+	header   http.Header
+	template *template.Template
+	secret   string
+
+	url        string
+	httpClient *http.Client
+}
+
+func parseWebhookTemplate(body string) (*template.Template, error) {
+	if body == "" {
+		body = DefaultWebhookTemplate
+	}
+
+	return template.New("webhook").Funcs(webhookFuncs).Parse(body)
+}
+
+// ValidateWebhookTemplate : リクエストボディのテンプレートをチェックする
+func ValidateWebhookTemplate(body string) error {
+	_, err := parseWebhookTemplate(body)
+	return err
+}
+
+// NewWebhook : Create Notifier instance for HTTP webhook
+//
+// body は Message を渡す text/template で、空の場合は DefaultWebhookTemplate を使う。
+// secret を指定した場合はリクエストボディの HMAC-SHA256 を WebhookSignatureHeader で送る。
+// テンプレートが不正な場合は nil を返す。
+func NewWebhook(method, webhookURL string, header http.Header, body, secret string) Notifier {
+	t, err := parseWebhookTemplate(body)
+	if err != nil {
+		return nil
+	}
+
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	return &webhook{
+		method:   method,
+		header:   header,
+		template: t,
+		secret:   secret,
+
+		url:        webhookURL,
+		httpClient: &http.Client{},
+	}
+}
+
+// payload : テンプレートから作ったリクエストボディ
+func (n *webhook) payload(msg *Message) ([]byte, error) {
+	t, err := n.template.Clone()
+	if err != nil {
+		return nil, err
+	}
+	t.Funcs(template.FuncMap{"precip": msg.Format().Precip})
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, msg); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// signature : リクエストボディの署名 (sha256=<hex>)
+func (n *webhook) signature(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(n.secret))
+	mac.Write(body)
+
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Notify : Notifier.Notify の実装
+func (n *webhook) Notify(msg *Message) error {
+	body, err := n.payload(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(n.method, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.header {
+		req.Header[k] = v
+	}
+	if n.secret != "" {
+		req.Header.Set(WebhookSignatureHeader, n.signature(body))
+	}
+
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	return webhookError{
+		Status: res.StatusCode,
+		Body:   strings.TrimSpace(string(b)),
+	}
+}
@@ -0,0 +1,413 @@
+package weatherline
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookError_Error(t *testing.T) {
+	err := webhookError{
+		Status: 404,
+		Body:   "webhook not found",
+	}
+
+	expected := "404: webhook not found"
+
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestValidateWebhookTemplate(t *testing.T) {
+	tests := []struct {
+		body string
+
+		expected bool
+	}{
+		// TEST0 {{{
+		{
+			body: "",
+
+			expected: true,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			body: `{"message": {{json (trim .Text)}}}`,
+
+			expected: true,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			body: `{"message": {{json .Text}`,
+
+			expected: false,
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			body: `{"message": {{unknown .Text}}}`,
+
+			expected: false,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateWebhookTemplate(tt.body)
+			if tt.expected && err != nil {
+				t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+			} else if !tt.expected && err == nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+		})
+	}
+}
+
+func TestNewWebhook(t *testing.T) {
+	tests := []struct {
+		method string
+		body   string
+
+		expectedNil    bool
+		expectedMethod string
+	}{
+		// TEST0 {{{
+		{
+			method: "",
+			body:   "",
+
+			expectedNil:    false,
+			expectedMethod: http.MethodPost,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			method: http.MethodPut,
+			body:   `{{.Text}}`,
+
+			expectedNil:    false,
+			expectedMethod: http.MethodPut,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			method: http.MethodPost,
+			body:   `{{.Text`,
+
+			expectedNil: true,
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			webhookURL := "https://example.com/hooks/weather"
+			header := http.Header{"Authorization": []string{"Bearer XXXX"}}
+
+			notifier := NewWebhook(tt.method, webhookURL, header, tt.body, "secret")
+			if tt.expectedNil {
+				if notifier != nil {
+					t.Fatal("Expected nil, but not.")
+				}
+				return
+			}
+			if notifier == nil {
+				t.Fatal("function returns nil")
+			}
+
+			n, ok := notifier.(*webhook)
+			if !ok {
+				t.Fatal("Expected webhook instance, but not.")
+			}
+
+			if n.method != tt.expectedMethod {
+				t.Fatalf("Expected method is %s, but it's %s.", tt.expectedMethod, n.method)
+			}
+
+			if n.url != webhookURL {
+				t.Fatalf("Expected url is %s, but it's %s.", webhookURL, n.url)
+			}
+
+			if n.header.Get("Authorization") != "Bearer XXXX" {
+				t.Fatalf("Expected header is %v, but it's %v.", header, n.header)
+			}
+
+			if n.secret != "secret" {
+				t.Fatalf("Expected secret is secret, but it's %s.", n.secret)
+			}
+
+			if n.httpClient == nil {
+				t.Fatal("httpClient is nil")
+			}
+		})
+	}
+}
+
+func TestWebhook_payload(t *testing.T) {
+	tokyo := loadLocation("Asia/Tokyo")
+
+	msg := &Message{
+		Date:   time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+		Days:   3,
+		Text:   "\n01/31\n  00:00 ☀ 2.1℃/-1.8℃ 0%\n",
+		Report: unmarshal(readFile("testdata/weatherline/cmd/run.json")).Report(),
+	}
+
+	tests := []struct {
+		body string
+		msg  *Message
+
+		expected    string
+		expectedErr bool
+	}{
+		// TEST0 {{{
+		{
+			body: "",
+
+			expected: `{"title": "Weather forecast 01/31 (Wed)", "text": "\n01/31\n  00:00 ☀ 2.1℃/-1.8℃ 0%\n"}`,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			body: `{{range $i, $p := .HourlyPoints}}{{if lt $i 3}}{{$p.Time.Format "15:04"}} {{icon $p.Weather}} {{value "%.1f" $p.Temperature}} {{precip $p.Weather $p.PrecipProbability $p.PrecipAccumulation}}
+{{end}}{{end}}`,
+
+			expected: "00:00 ☀ 2.1 0%\n01:00 ☀ 1.9 0%\n02:00 ☀ 1.6 0%\n",
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			body: `{{range .DailyPoints}}{{.Time.Format "01/02"}} {{.Summary}} {{time "15:04" .ApparentTemperatureHighTime}}
+{{end}}{{.Report.Location}}`,
+
+			expected: "02/01 一日中曇り。 14:00\n02/02 一日中曇り。 17:00\n02/03 一日中薄曇り。 14:00\nAsia/Tokyo",
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			body: `{{.Unknown}}`,
+
+			expectedErr: true,
+		},
+		// }}}
+		// TEST4 {{{
+		{
+			body: `{{range .HourlyPoints}}{{$.Format.Temperature .Temperature}} {{precip .Weather .PrecipProbability .PrecipAccumulation}}{{end}}`,
+			msg: &Message{
+				Date: time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo),
+				Report: &Report{
+					Location: tokyo,
+					Units:    UnitsUS,
+					Hourly: []HourlyPoint{
+						{
+							Time:               time.Date(2018, 1, 31, 9, 0, 0, 0, tokyo),
+							Weather:            WeatherSnow,
+							Temperature:        28.4,
+							PrecipProbability:  0.8,
+							PrecipAccumulation: 1.26,
+						},
+					},
+				},
+			},
+
+			expected: "28.4℉ 80%/1.3in",
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := parseWebhookTemplate(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := msg
+			if tt.msg != nil {
+				m = tt.msg
+			}
+
+			n := &webhook{template: tmpl}
+			b, err := n.payload(m)
+			if err != nil {
+				if !tt.expectedErr {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				}
+				return
+			} else if tt.expectedErr {
+				t.Fatal("It was expected that an error occurred, but it did not occur")
+			}
+
+			actual := string(b)
+			if actual != tt.expected {
+				t.Errorf("Expected to get [%s], but got [%s]", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWebhook_signature(t *testing.T) {
+	n := &webhook{secret: "It's a Secret to Everybody"}
+
+	expected := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+
+	actual := n.signature([]byte("Hello, World!"))
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func webhookFunc(method, secret string, resStatus int, resBody string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			fmt.Fprint(w, "method not allowed")
+			return
+		}
+
+		if r.Header.Get("X-Api-Key") != "XXXX" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, "unauthorized")
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if secret != "" {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if !hmac.Equal([]byte(r.Header.Get(WebhookSignatureHeader)), []byte(expected)) {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, "invalid signature")
+				return
+			}
+		}
+
+		w.WriteHeader(resStatus)
+		fmt.Fprint(w, resBody)
+	}
+}
+
+func TestWebhook_Notify(t *testing.T) {
+	tests := []struct {
+		method       string
+		secret       string
+		serverSecret string
+		resStatus    int
+		resBody      string
+
+		expected error
+	}{
+		// TEST0 {{{
+		{
+			method:       http.MethodPost,
+			secret:       "",
+			serverSecret: "",
+			resStatus:    http.StatusOK,
+			resBody:      "ok",
+
+			expected: nil,
+		},
+		// }}}
+		// TEST1 {{{
+		{
+			method:       http.MethodPut,
+			secret:       "shared",
+			serverSecret: "shared",
+			resStatus:    http.StatusNoContent,
+			resBody:      "",
+
+			expected: nil,
+		},
+		// }}}
+		// TEST2 {{{
+		{
+			method:       http.MethodPost,
+			secret:       "wrong",
+			serverSecret: "shared",
+			resStatus:    http.StatusOK,
+			resBody:      "ok",
+
+			expected: webhookError{
+				Status: http.StatusForbidden,
+				Body:   "invalid signature",
+			},
+		},
+		// }}}
+		// TEST3 {{{
+		{
+			method:       http.MethodPost,
+			secret:       "",
+			serverSecret: "",
+			resStatus:    http.StatusInternalServerError,
+			resBody:      "internal error\n",
+
+			expected: webhookError{
+				Status: http.StatusInternalServerError,
+				Body:   "internal error",
+			},
+		},
+		// }}}
+	}
+
+	for i, tt := range tests {
+		tt := tt // capture
+		t.Run(fmt.Sprintf("%02d", i), func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(webhookFunc(tt.method, tt.serverSecret, tt.resStatus, tt.resBody)))
+			defer server.Close()
+
+			tmpl, err := parseWebhookTemplate("")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			n := &webhook{
+				method:   tt.method,
+				header:   http.Header{"X-Api-Key": []string{"XXXX"}},
+				template: tmpl,
+				secret:   tt.secret,
+
+				url:        server.URL + "/hooks/weather",
+				httpClient: server.Client(),
+			}
+
+			err = n.Notify(&Message{Text: "TEST"})
+			if err != nil {
+				if tt.expected == nil {
+					t.Errorf("Expected no error occurred, but it occurred (%v)", err)
+				} else if err != tt.expected {
+					t.Errorf("Expected to get [%v], but got [%v]", tt.expected, err)
+				}
+			} else if tt.expected != nil {
+				t.Errorf("It was expected that an error occurred, but it did not occur")
+			}
+		})
+	}
+}
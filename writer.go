@@ -0,0 +1,41 @@
+package weatherline
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+type writer struct {
+	w io.Writer
+}
+
+// NewWriter : Create Notifier instance which writes forecast to w (e.g. os.Stdout)
+func NewWriter(w io.Writer) Notifier {
+	return &writer{
+		w: w,
+	}
+}
+
+// Notify : Notifier.Notify の実装
+func (n *writer) Notify(msg *Message) error {
+	_, err := io.WriteString(n.w, msg.Text)
+	return err
+}
+
+type file struct {
+	path string
+}
+
+// NewFile : Create Notifier instance which writes forecast to the file
+//
+// ファイルが既にある場合は上書きする。
+func NewFile(path string) Notifier {
+	return &file{
+		path: path,
+	}
+}
+
+// Notify : Notifier.Notify の実装
+func (n *file) Notify(msg *Message) error {
+	return ioutil.WriteFile(n.path, []byte(msg.Text), 0644)
+}
@@ -0,0 +1,97 @@
+package weatherline
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	notifier := NewWriter(&buf)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*writer)
+	if !ok {
+		t.Fatal("Expected writer instance, but not.")
+	}
+
+	if n.w != &buf {
+		t.Fatal("Expected w is the given writer, but not.")
+	}
+}
+
+func TestWriter_Notify(t *testing.T) {
+	var buf bytes.Buffer
+	n := &writer{w: &buf}
+
+	expected := readFile("testdata/weatherline/cmd/run00.txt")
+
+	if err := n.Notify(&Message{Text: expected}); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := buf.String()
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+}
+
+func TestNewFile(t *testing.T) {
+	path := "/tmp/weatherline.txt"
+
+	notifier := NewFile(path)
+	if notifier == nil {
+		t.Fatal("function returns nil")
+	}
+
+	n, ok := notifier.(*file)
+	if !ok {
+		t.Fatal("Expected file instance, but not.")
+	}
+
+	if n.path != path {
+		t.Fatalf("Expected path is %s, but it's %s.", path, n.path)
+	}
+}
+
+func TestFile_Notify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "weatherline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "forecast.txt")
+	if err := ioutil.WriteFile(path, []byte("old forecast\nold forecast\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &file{path: path}
+
+	expected := readFile("testdata/weatherline/cmd/run00.txt")
+
+	if err := n.Notify(&Message{Text: expected}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := string(b)
+	if actual != expected {
+		t.Errorf("Expected to get [%s], but got [%s]", expected, actual)
+	}
+
+	n = &file{path: filepath.Join(dir, "not", "exist", "forecast.txt")}
+	if err := n.Notify(&Message{Text: expected}); err == nil {
+		t.Errorf("It was expected that an error occurred, but it did not occur")
+	}
+}